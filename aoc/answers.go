@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// answers are the known answers of a day. The answers file of a day has one
+// line per line of output of a solution:
+//
+//	<input> <part> <answer>
+//
+// The input is the name of the input file in the directory of the day. A
+// part with an answer over several lines, like a picture, has several lines.
+// Empty lines and lines starting with # are ignored.
+type answers struct {
+	// input files in the order they appear
+	inputs []string
+	lines  map[string][]answerLine
+}
+
+type answerLine struct {
+	part int
+	text string
+}
+
+func readAnswers(input io.Reader) (*answers, error) {
+	a := &answers{
+		lines: map[string][]answerLine{},
+	}
+	scanner := bufio.NewScanner(input)
+	lineNr := 0
+	for scanner.Scan() {
+		lineNr++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected '<input> <part> <answer>'", lineNr)
+		}
+		part, err := strconv.Atoi(fields[1])
+		if err != nil || part < 1 {
+			return nil, fmt.Errorf("line %d: invalid part '%s'", lineNr, fields[1])
+		}
+		input := fields[0]
+		if _, ok := a.lines[input]; !ok {
+			a.inputs = append(a.inputs, input)
+		}
+		a.lines[input] = append(a.lines[input], answerLine{part, fields[2]})
+	}
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+	return a, nil
+}
+
+func readAnswersFile(file string) (*answers, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := readAnswers(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return a, nil
+}
+
+// parts returns the parts with an answer for input.
+func (a *answers) parts(input string) []int {
+	parts := []int{}
+	for _, l := range a.lines[input] {
+		if len(parts) == 0 || parts[len(parts)-1] != l.part {
+			parts = append(parts, l.part)
+		}
+	}
+	return parts
+}
+
+// check compares the output of a solution for input line by line with the
+// answers and returns whether each part is correct. Output after the last
+// answer makes the last part wrong.
+func (a *answers) check(input string, output []string) map[int]bool {
+	expected := a.lines[input]
+	correct := map[int]bool{}
+	for i, l := range expected {
+		if _, ok := correct[l.part]; !ok {
+			correct[l.part] = true
+		}
+		if i >= len(output) || strings.TrimRight(output[i], " \r") != l.text {
+			correct[l.part] = false
+		}
+	}
+	if len(expected) > 0 && len(output) > len(expected) {
+		correct[expected[len(expected)-1].part] = false
+	}
+	return correct
+}
+
+// outputLines splits the output of a solution into lines.
+func outputLines(output []byte) []string {
+	text := strings.TrimRight(string(output), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"text/tabwriter"
+)
+
+func Test_readAnswers(t *testing.T) {
+	a, err := readAnswers(strings.NewReader(`# comment
+sample.txt 1 15
+sample.txt 2 #..#
+sample.txt 2 .##.
+
+input.txt 1 CMZ
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.inputs, []string{"sample.txt", "input.txt"}) {
+		t.Fatalf("got=%v, want=[sample.txt input.txt]", a.inputs)
+	}
+	if parts := a.parts("sample.txt"); !reflect.DeepEqual(parts, []int{1, 2}) {
+		t.Fatalf("got=%v, want=[1 2]", parts)
+	}
+
+	for _, test := range []struct {
+		output   string
+		expected map[int]bool
+	}{
+		{"15\n#..#\n.##.\n", map[int]bool{1: true, 2: true}},
+		{"14\n#..#\n.##.\n", map[int]bool{1: false, 2: true}},
+		{"15\n#..#\n", map[int]bool{1: true, 2: false}},
+		{"15\n#..#\n.##.\n3\n", map[int]bool{1: true, 2: false}},
+		{"", map[int]bool{1: false, 2: false}},
+	} {
+		result := a.check("sample.txt", outputLines([]byte(test.output)))
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("%q: got=%v, want=%v", test.output, result, test.expected)
+		}
+	}
+
+	for _, input := range []string{"sample.txt 15", "sample.txt x 15", "sample.txt 0 15"} {
+		_, err := readAnswers(strings.NewReader(input))
+		if err == nil {
+			t.Fatalf("%s: expected error", input)
+		}
+	}
+}
+
+type answerColumn struct {
+	input string
+	part  int
+}
+
+// Test_answers builds the solution of every day in every language and
+// compares its output for the inputs in the answers file of the day.
+// Solutions without an installed toolchain are skipped, as are inputs which
+// are not there, like the real inputs of most days, or which can not be
+// decrypted. The matrix of the results is logged, use
+// go test -v -count=1 -run Test_answers to see it on every run.
+func Test_answers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds every solution")
+	}
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solutions, err := findSolutions(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	k, err := loadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []answerColumn{}
+	results := map[string]map[answerColumn]string{}
+	days := []string{}
+	for _, s := range solutions {
+		day := fmt.Sprintf("%d-day%02d-%s", s.year, s.day, s.lang)
+		dir := filepath.Dir(s.dir)
+		days = append(days, day)
+		results[day] = map[answerColumn]string{}
+		t.Run(day, func(t *testing.T) {
+			a, err := readAnswersFile(filepath.Join(dir, "answers.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prog, err := buildSolution(s, bin)
+			var skip *skipError
+			if errors.As(err, &skip) {
+				t.Skip(skip)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range a.inputs {
+				for _, part := range a.parts(input) {
+					col := answerColumn{input, part}
+					if !containsColumn(columns, col) {
+						columns = append(columns, col)
+					}
+					results[day][col] = "-"
+				}
+				file, ok, err := k.resolveInput(filepath.Join(dir, input), bin)
+				if err != nil || !ok {
+					t.Logf("%s: skipped: %v", input, err)
+					continue
+				}
+
+				out, _, err := prog(file)
+				if err != nil {
+					t.Errorf("%s: %s", input, err)
+				}
+				output := outputLines(out)
+				for part, ok := range a.check(input, output) {
+					if ok {
+						results[day][answerColumn{input, part}] = "ok"
+						continue
+					}
+					results[day][answerColumn{input, part}] = "FAIL"
+					t.Errorf("%s: part %d: wrong answer, output:\n%s", input, part, strings.Join(output, "\n"))
+				}
+			}
+		})
+	}
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "day")
+	for _, col := range columns {
+		fmt.Fprintf(tw, "\t%s %d", strings.TrimSuffix(col.input, ".txt"), col.part)
+	}
+	fmt.Fprintln(tw)
+	for _, day := range days {
+		fmt.Fprint(tw, day)
+		for _, col := range columns {
+			result, ok := results[day][col]
+			if !ok {
+				result = "-"
+			}
+			fmt.Fprintf(tw, "\t%s", result)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	t.Logf("results:\n%s", sb.String())
+}
+
+func containsColumn(columns []answerColumn, col answerColumn) bool {
+	for _, c := range columns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// usage is the resources used by a run of a program.
+type usage struct {
+	wall   time.Duration
+	user   time.Duration
+	sys    time.Duration
+	maxRSS int64
+}
+
+// add adds the times of u and keeps the highest peak RSS.
+func (u *usage) add(o usage) {
+	u.wall += o.wall
+	u.user += o.user
+	u.sys += o.sys
+	if o.maxRSS > u.maxRSS {
+		u.maxRSS = o.maxRSS
+	}
+}
+
+// benchResult is the result of running a solution for an input several
+// times. The times are means over the runs.
+type benchResult struct {
+	Year    int    `json:"year"`
+	Day     int    `json:"day"`
+	Lang    string `json:"lang"`
+	Input   string `json:"input"`
+	Runs    int    `json:"runs"`
+	WallNS  int64  `json:"wall_ns"`
+	MinNS   int64  `json:"min_wall_ns"`
+	UserNS  int64  `json:"user_ns"`
+	SysNS   int64  `json:"sys_ns"`
+	MaxRSS  int64  `json:"max_rss_bytes"`
+	Error   string `json:"error,omitempty"`
+	Changed string `json:"-"`
+}
+
+func (r benchResult) key() string {
+	return fmt.Sprintf("%d/%d/%s/%s", r.Year, r.Day, r.Lang, r.Input)
+}
+
+type benchReport struct {
+	Date    time.Time     `json:"date"`
+	Results []benchResult `json:"results"`
+}
+
+// bench runs prog runs times with input.
+func bench(prog program, input string, runs int) (benchResult, error) {
+	r := benchResult{Runs: runs}
+	total := usage{}
+	min := time.Duration(0)
+	for i := 0; i < runs; i++ {
+		_, u, err := prog(input)
+		if err != nil {
+			return r, err
+		}
+		if i == 0 || u.wall < min {
+			min = u.wall
+		}
+		total.add(u)
+	}
+	n := int64(runs)
+	r.WallNS = int64(total.wall) / n
+	r.MinNS = int64(min)
+	r.UserNS = int64(total.user) / n
+	r.SysNS = int64(total.sys) / n
+	r.MaxRSS = total.maxRSS
+	return r, nil
+}
+
+// benchSolutions builds the solutions and runs them runs times with each
+// input of their day. Solutions which can not be built here are skipped.
+func benchSolutions(solutions []solution, k *keys, inputs []string, runs int, log io.Writer) ([]benchResult, error) {
+	out, err := os.MkdirTemp("", "aoc-bench")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+
+	results := []benchResult{}
+	for _, s := range solutions {
+		prog, err := buildSolution(s, out)
+		var skip *skipError
+		if errors.As(err, &skip) {
+			fmt.Fprintf(log, "%s: skipped: %s\n", s.name(), skip)
+			continue
+		}
+		for _, input := range inputs {
+			file, ok, resolveErr := k.resolveInput(filepath.Join(filepath.Dir(s.dir), input), out)
+			if !ok && resolveErr == nil {
+				continue
+			}
+			r := benchResult{}
+			runErr := err
+			if runErr == nil {
+				runErr = resolveErr
+			}
+			if runErr == nil {
+				r, runErr = bench(prog, file, runs)
+			}
+			if runErr != nil {
+				r.Error = runErr.Error()
+				fmt.Fprintf(log, "%s: %s: %s\n", s.name(), input, runErr)
+			}
+			r.Year, r.Day, r.Lang, r.Input = s.year, s.day, s.lang, input
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// compareBench marks the results which are slower than in the previous
+// report by more than threshold, e.g. 0.2 for 20%, and returns the number
+// of regressions.
+func compareBench(results []benchResult, previous *benchReport, threshold float64) int {
+	if previous == nil {
+		return 0
+	}
+	prev := map[string]benchResult{}
+	for _, r := range previous.Results {
+		prev[r.key()] = r
+	}
+	regressions := 0
+	for i, r := range results {
+		p, ok := prev[r.key()]
+		if !ok || r.Error != "" || p.Error != "" || p.WallNS == 0 {
+			continue
+		}
+		change := float64(r.WallNS-p.WallNS) / float64(p.WallNS)
+		results[i].Changed = fmt.Sprintf("%+.0f%%", change*100)
+		if change > threshold {
+			results[i].Changed += " REGRESSION"
+			regressions++
+		}
+	}
+	return regressions
+}
+
+func formatBytes(b int64) string {
+	if b == 0 {
+		return "-"
+	}
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	value, suffix := float64(b)/unit, "KiB"
+	for _, s := range []string{"MiB", "GiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+func formatNS(ns int64) string {
+	return time.Duration(ns).Round(time.Microsecond).String()
+}
+
+// writeBenchMarkdown writes the results as markdown table.
+func writeBenchMarkdown(w io.Writer, results []benchResult) error {
+	_, err := fmt.Fprintln(w, "| year | day | language | input | wall | min wall | user | sys | peak RSS | change |")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "|-----:|----:|----------|-------|-----:|---------:|-----:|----:|---------:|-------:|")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(w, "| %d | %d | %s | %s | error | | | | | |\n", r.Year, r.Day, r.Lang, r.Input)
+			continue
+		}
+		fmt.Fprintf(w, "| %d | %d | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			r.Year, r.Day, r.Lang, r.Input, formatNS(r.WallNS), formatNS(r.MinNS),
+			formatNS(r.UserNS), formatNS(r.SysNS), formatBytes(r.MaxRSS), r.Changed)
+	}
+	return nil
+}
+
+func readBenchReport(file string) (*benchReport, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	report := &benchReport{}
+	err = json.Unmarshal(data, report)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	// reports from before the years were added
+	for i := range report.Results {
+		if report.Results[i].Year == 0 {
+			report.Results[i].Year = legacyYear
+		}
+	}
+	return report, nil
+}
+
+func writeBenchReport(file string, report *benchReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+func benchCmd(args []string) error {
+	var (
+		root      string
+		year      int
+		runs      int
+		jsonFile  string
+		mdFile    string
+		threshold float64
+	)
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", 0, "only time the days of `year`, all years by default")
+	fs.IntVar(&runs, "n", 5, "number of runs per solution and input")
+	fs.StringVar(&jsonFile, "json", "bench.json", "results of the last run, relative to the root")
+	fs.StringVar(&mdFile, "md", "bench.md", "markdown table of the results, relative to the root")
+	fs.Float64Var(&threshold, "threshold", 0.2, "flag results slower than the last run by more than this fraction")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc bench [flags] [day...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if runs < 1 {
+		return fmt.Errorf("invalid number of runs %d", runs)
+	}
+	var err error
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+	solutions, err := findSolutions(root)
+	if err != nil {
+		return err
+	}
+	solutions, err = selectDays(solutions, year, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	jsonFile = filepath.Join(root, jsonFile)
+	previous, err := readBenchReport(jsonFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	k, err := loadKeys()
+	if err != nil {
+		return err
+	}
+	results, err := benchSolutions(solutions, k, []string{"sample.txt", "input.txt"}, runs, os.Stderr)
+	if err != nil {
+		return err
+	}
+	regressions := compareBench(results, previous, threshold)
+
+	err = writeBenchMarkdown(os.Stdout, results)
+	if err != nil {
+		return err
+	}
+	err = writeBenchReport(jsonFile, &benchReport{
+		Date:    time.Now().UTC(),
+		Results: results,
+	})
+	if err != nil {
+		return err
+	}
+	err = writeFile(filepath.Join(root, mdFile), func(w io.Writer) error {
+		return writeBenchMarkdown(w, results)
+	})
+	if err != nil {
+		return err
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d regressions over %.0f%%", regressions, threshold*100)
+	}
+	return nil
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
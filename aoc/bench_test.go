@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_bench(t *testing.T) {
+	walls := []time.Duration{3 * time.Millisecond, time.Millisecond, 2 * time.Millisecond}
+	runs := 0
+	prog := func(input string) ([]byte, usage, error) {
+		u := usage{wall: walls[runs], user: time.Millisecond, maxRSS: int64(runs + 1)}
+		runs++
+		return nil, u, nil
+	}
+	r, err := bench(prog, "sample.txt", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := benchResult{
+		Runs:   3,
+		WallNS: int64(2 * time.Millisecond),
+		MinNS:  int64(time.Millisecond),
+		UserNS: int64(time.Millisecond),
+		MaxRSS: 3,
+	}
+	if r != expected {
+		t.Fatalf("got=%+v, want=%+v", r, expected)
+	}
+
+	_, err = bench(func(string) ([]byte, usage, error) {
+		return nil, usage{}, errors.New("exit status 1")
+	}, "sample.txt", 3)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_compareBench(t *testing.T) {
+	previous := &benchReport{
+		Results: []benchResult{
+			{Year: 2022, Day: 1, Lang: "sh", Input: "sample.txt", WallNS: 100},
+			{Year: 2022, Day: 2, Lang: "go", Input: "sample.txt", WallNS: 100},
+			{Year: 2022, Day: 3, Lang: "c", Input: "sample.txt", WallNS: 100},
+		},
+	}
+	results := []benchResult{
+		{Year: 2022, Day: 1, Lang: "sh", Input: "sample.txt", WallNS: 110},
+		{Year: 2022, Day: 2, Lang: "go", Input: "sample.txt", WallNS: 150},
+		{Year: 2022, Day: 3, Lang: "c", Input: "input.txt", WallNS: 500},
+	}
+	regressions := compareBench(results, previous, 0.2)
+	if regressions != 1 {
+		t.Fatalf("got=%d, want=1", regressions)
+	}
+	for i, expected := range []string{"+10%", "+50% REGRESSION", ""} {
+		if results[i].Changed != expected {
+			t.Fatalf("%d: got=%q, want=%q", i, results[i].Changed, expected)
+		}
+	}
+	if compareBench(results, nil, 0.2) != 0 {
+		t.Fatal("regressions without previous report")
+	}
+}
+
+func Test_writeBenchMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := writeBenchMarkdown(buf, []benchResult{
+		{Year: 2022, Day: 8, Lang: "go", Input: "input.txt", WallNS: 5_400_000, MinNS: 5_300_000, UserNS: 2_600_000, SysNS: 2_500_000, MaxRSS: 5 << 20},
+		{Year: 2022, Day: 11, Lang: "rust", Input: "sample.txt", Error: "build failed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		"| 2022 | 8 | go | input.txt | 5.4ms | 5.3ms | 2.6ms | 2.5ms | 5.0 MiB |  |",
+		"| 2022 | 11 | rust | sample.txt | error | | | | | |",
+	}
+	if len(lines) != 4 || lines[2] != expected[0] || lines[3] != expected[1] {
+		t.Fatalf("got=%q, want=%q", lines[2:], expected)
+	}
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The personal puzzle inputs are stored encrypted as input.txt.enc. A file
+// starts with a header, followed by the nonce and the AES-256-GCM sealed
+// input:
+//
+//	"aocenc1\n" | kdf (1 byte) | salt (16 bytes) | nonce (12 bytes) | ciphertext
+//
+// The key is either read from a key file or derived from a passphrase with
+// PBKDF2-HMAC-SHA256 and the salt. The header is authenticated as well.
+const encSuffix = ".enc"
+
+var encMagic = []byte("aocenc1\n")
+
+const (
+	kdfKeyFile byte = iota
+	kdfPassphrase
+)
+
+const (
+	keySize          = 32
+	saltSize         = 16
+	pbkdf2Iterations = 600_000
+)
+
+// keys holds the secrets to encrypt and decrypt inputs. The key file is
+// read from $AOC_KEY_FILE or aoc/key in the user config directory and the
+// passphrase from $AOC_PASSPHRASE.
+type keys struct {
+	keyFile    string
+	key        []byte
+	passphrase string
+
+	// derived keys by salt, deriving a key takes a while
+	derived map[string][]byte
+}
+
+// defaultKeyFile returns the key file used if $AOC_KEY_FILE is not set.
+func defaultKeyFile() (string, error) {
+	if file := os.Getenv("AOC_KEY_FILE"); file != "" {
+		return file, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "key"), nil
+}
+
+// loadKeys returns the available secrets. No secrets at all is no error,
+// plaintext inputs still work.
+func loadKeys() (*keys, error) {
+	k := &keys{
+		passphrase: os.Getenv("AOC_PASSPHRASE"),
+		derived:    map[string][]byte{},
+	}
+	var err error
+	k.keyFile, err = defaultKeyFile()
+	if err != nil {
+		return nil, err
+	}
+	k.key, err = readKeyFile(k.keyFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return k, nil
+}
+
+// readKeyFile reads a hex encoded key.
+func readKeyFile(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("%s: expected %d hex encoded bytes", file, keySize)
+	}
+	return key, nil
+}
+
+// generateKeyFile writes a new random key to file. An existing key file is
+// never overwritten, the inputs encrypted with it would be lost.
+func generateKeyFile(file string) ([]byte, error) {
+	key := make([]byte, keySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0o700)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintln(f, hex.EncodeToString(key))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
+
+// pbkdf2 derives a key of keyLen bytes from password and salt as in RFC 8018
+// with HMAC-SHA256.
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := []byte{}
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// keyFor returns the key for the kdf of a file.
+func (k *keys) keyFor(kdf byte, salt []byte) ([]byte, error) {
+	switch kdf {
+	case kdfKeyFile:
+		if k == nil || k.key == nil {
+			return nil, fmt.Errorf("no key file, set AOC_KEY_FILE or create %s", k.keyFileName())
+		}
+		return k.key, nil
+	case kdfPassphrase:
+		if k == nil || k.passphrase == "" {
+			return nil, fmt.Errorf("no passphrase, set AOC_PASSPHRASE")
+		}
+		key, ok := k.derived[string(salt)]
+		if !ok {
+			key = pbkdf2([]byte(k.passphrase), salt, pbkdf2Iterations, keySize)
+			k.derived[string(salt)] = key
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unknown key derivation %d", kdf)
+	}
+}
+
+func (k *keys) keyFileName() string {
+	if k == nil || k.keyFile == "" {
+		return "a key file"
+	}
+	return k.keyFile
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt encrypts plaintext with the key file or, if there is none, with
+// the passphrase.
+func (k *keys) encrypt(plaintext []byte) ([]byte, error) {
+	kdf := kdfKeyFile
+	if k == nil || k.key == nil {
+		kdf = kdfPassphrase
+	}
+	salt := make([]byte, saltSize)
+	if kdf == kdfPassphrase {
+		_, err := rand.Read(salt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key, err := k.keyFor(kdf, salt)
+	if err != nil {
+		return nil, fmt.Errorf("no key file or passphrase: set AOC_KEY_FILE, AOC_PASSPHRASE or use -genkey")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append(append(append([]byte{}, encMagic...), kdf), salt...)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	data := append(append([]byte{}, header...), nonce...)
+	return gcm.Seal(data, nonce, plaintext, header), nil
+}
+
+func (k *keys) decrypt(data []byte) ([]byte, error) {
+	headerSize := len(encMagic) + 1 + saltSize
+	if len(data) < headerSize || !bytes.Equal(data[:len(encMagic)], encMagic) {
+		return nil, fmt.Errorf("not an encrypted input")
+	}
+	header := data[:headerSize]
+	kdf := header[len(encMagic)]
+	salt := header[len(encMagic)+1:]
+	key, err := k.keyFor(kdf, salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	rest := data[headerSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted input too short")
+	}
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, fmt.Errorf("wrong key or damaged input")
+	}
+	return plaintext, nil
+}
+
+// readInput reads file or, if it does not exist, decrypts file.enc.
+func (k *keys) readInput(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if !errors.Is(err, os.ErrNotExist) {
+		return data, err
+	}
+	data, encErr := os.ReadFile(file + encSuffix)
+	if errors.Is(encErr, os.ErrNotExist) {
+		// report the missing plaintext input
+		return nil, err
+	}
+	if encErr != nil {
+		return nil, encErr
+	}
+	plaintext, err := k.decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s: %w", file, encSuffix, err)
+	}
+	return plaintext, nil
+}
+
+// resolveInput returns a plaintext file with the input of file for programs
+// which read the input themselves. An encrypted input is decrypted into dir.
+// If there is neither file nor file.enc it returns false.
+func (k *keys) resolveInput(file, dir string) (string, bool, error) {
+	if _, err := os.Stat(file); err == nil {
+		return file, true, nil
+	}
+	if _, err := os.Stat(file + encSuffix); err != nil {
+		return "", false, nil
+	}
+	plaintext, err := k.readInput(file)
+	if err != nil {
+		return "", false, err
+	}
+	f, err := os.CreateTemp(dir, "input-*-"+filepath.Base(file))
+	if err != nil {
+		return "", false, err
+	}
+	_, err = f.Write(plaintext)
+	if err != nil {
+		f.Close()
+		return "", false, err
+	}
+	return f.Name(), true, f.Close()
+}
+
+// encryptInput writes file.enc for file unless it exists with the same
+// input. It returns whether file.enc was written.
+func (k *keys) encryptInput(file string) (bool, error) {
+	plaintext, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	existing, err := os.ReadFile(file + encSuffix)
+	if err == nil {
+		decrypted, err := k.decrypt(existing)
+		if err == nil && bytes.Equal(decrypted, plaintext) {
+			return false, nil
+		}
+	}
+	data, err := k.encrypt(plaintext)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(file+encSuffix, data, 0o644)
+}
+
+func encryptCmd(args []string) error {
+	var (
+		root   string
+		year   int
+		remove bool
+		genKey bool
+	)
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", 0, "only encrypt the inputs of `year`, all years by default")
+	fs.BoolVar(&remove, "rm", false, "remove the plaintext inputs after encrypting them")
+	fs.BoolVar(&genKey, "genkey", false, "create a new key file if there is none")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc encrypt [flags]\n\nencrypts every input.txt to input.txt.enc\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	k, err := loadKeys()
+	if err != nil {
+		return err
+	}
+	if genKey && k.key == nil {
+		k.key, err = generateKeyFile(k.keyFile)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created key file %s, keep a copy of it\n", k.keyFile)
+	}
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+	years, err := findYears(root)
+	if err != nil {
+		return err
+	}
+
+	for _, y := range years {
+		if year != 0 && y != year {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(yearDir(root, y), "day[0-9][0-9]", "input.txt"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			written, err := k.encryptInput(file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			if written {
+				fmt.Printf("encrypted %s\n", file)
+			}
+			if !remove {
+				continue
+			}
+			// only remove the input if it can be read again
+			data, err := os.ReadFile(file + encSuffix)
+			if err != nil {
+				return err
+			}
+			_, err = k.decrypt(data)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", file, encSuffix, err)
+			}
+			err = os.Remove(file)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("removed %s\n", file)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_pbkdf2(t *testing.T) {
+	for _, test := range []struct {
+		iterations int
+		expected   string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	} {
+		key := hex.EncodeToString(pbkdf2([]byte("password"), []byte("salt"), test.iterations, 32))
+		if key != test.expected {
+			t.Fatalf("%d iterations: got=%s, want=%s", test.iterations, key, test.expected)
+		}
+	}
+}
+
+func Test_encrypt(t *testing.T) {
+	dir := t.TempDir()
+	key, err := generateKeyFile(filepath.Join(dir, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = generateKeyFile(filepath.Join(dir, "key"))
+	if err == nil {
+		t.Fatal("existing key file overwritten")
+	}
+	read, err := readKeyFile(filepath.Join(dir, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, key) {
+		t.Fatal("key file differs from key")
+	}
+
+	input := []byte("30373\n25512\n")
+	for name, k := range map[string]*keys{
+		"key file":   {key: key},
+		"passphrase": {passphrase: "secret", derived: map[string][]byte{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := k.encrypt(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(data, input) {
+				t.Fatal("plaintext in encrypted input")
+			}
+			plaintext, err := k.decrypt(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(plaintext, input) {
+				t.Fatalf("got=%q, want=%q", plaintext, input)
+			}
+
+			// the header is authenticated
+			data[len(encMagic)+1] ^= 1
+			_, err = k.decrypt(data)
+			if err == nil {
+				t.Fatal("expected error for changed salt")
+			}
+		})
+	}
+
+	data, err := (&keys{key: key}).encrypt(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := make([]byte, keySize)
+	for _, k := range []*keys{nil, {key: other}, {passphrase: "secret", derived: map[string][]byte{}}} {
+		_, err = k.decrypt(data)
+		if err == nil {
+			t.Fatalf("%v: expected error", k)
+		}
+	}
+}
+
+func Test_resolveInput(t *testing.T) {
+	dir := t.TempDir()
+	k := &keys{key: make([]byte, keySize)}
+	file := filepath.Join(dir, "input.txt")
+	writeTestFile(t, file, "1\n2\n")
+
+	written, err := k.encryptInput(file)
+	if err != nil || !written {
+		t.Fatalf("got=%v, %v, want written", written, err)
+	}
+	written, err = k.encryptInput(file)
+	if err != nil || written {
+		t.Fatalf("got=%v, %v, want unchanged", written, err)
+	}
+
+	resolved, ok, err := k.resolveInput(file, dir)
+	if err != nil || !ok || resolved != file {
+		t.Fatalf("got=%s, %v, %v, want the plaintext input", resolved, ok, err)
+	}
+
+	err = os.Remove(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, ok, err = k.resolveInput(file, dir)
+	if err != nil || !ok {
+		t.Fatalf("got=%v, %v", ok, err)
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1\n2\n" {
+		t.Fatalf("got=%q, want=%q", data, "1\n2\n")
+	}
+
+	_, ok, err = k.resolveInput(filepath.Join(dir, "sample.txt"), dir)
+	if err != nil || ok {
+		t.Fatalf("got=%v, %v, want missing input", ok, err)
+	}
+
+	missing := filepath.Join(dir, "sample.txt")
+	_, err = k.readInput(missing)
+	if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), missing+":") {
+		t.Fatalf("got=%v, want missing %s", err, missing)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+// the days register their solvers
+import (
+	_ "day02/solution"
+	_ "day07/solution"
+	_ "day08/solution"
+	_ "day09/solution"
+	_ "day12/solution"
+)
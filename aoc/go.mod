@@ -0,0 +1,21 @@
+module aoc
+
+go 1.19
+
+require (
+	day02 v0.0.0
+	day07 v0.0.0
+	day08 v0.0.0
+	day09 v0.0.0
+	day12 v0.0.0
+	solver v0.0.0
+)
+
+replace (
+	day02 => ../day02/go
+	day07 => ../day07/go
+	day08 => ../day08/go
+	day09 => ../day09/go
+	day12 => ../day12/go
+	solver => ../solver
+)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// The days of a year are in YYYY/dayNN/<lang>. The days of legacyYear may
+// still be in dayNN/<lang> in the root directory, as they were before the
+// repository had more than one year. aoc migrate moves them.
+const legacyYear = 2022
+
+var (
+	yearDirPattern = regexp.MustCompile(`^\d{4}$`)
+	dayDirPattern  = regexp.MustCompile(`^day(\d\d)$`)
+)
+
+// findRoot returns the first directory from the working directory upwards
+// which contains the days.
+func findRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "prepare.go")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("repository root not found, use -root")
+		}
+		dir = parent
+	}
+}
+
+// hasLegacyDays returns whether there are days in the root directory.
+func hasLegacyDays(root string) (bool, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && dayDirPattern.MatchString(entry.Name()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// yearDir returns the directory with the days of a year.
+func yearDir(root string, year int) string {
+	dir := filepath.Join(root, strconv.Itoa(year))
+	if year != legacyYear {
+		return dir
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+	if legacy, _ := hasLegacyDays(root); legacy {
+		return root
+	}
+	return dir
+}
+
+func dayDir(root string, year, day int) string {
+	return filepath.Join(yearDir(root, year), fmt.Sprintf("day%02d", day))
+}
+
+// findYears returns the years with days in root in ascending order.
+func findYears(root string) ([]int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	years := []int{}
+	for _, entry := range entries {
+		if entry.IsDir() && yearDirPattern.MatchString(entry.Name()) {
+			year, _ := strconv.Atoi(entry.Name())
+			years = append(years, year)
+		}
+	}
+	legacy, err := hasLegacyDays(root)
+	if err != nil {
+		return nil, err
+	}
+	if legacy && !containsInt(years, legacyYear) {
+		years = append(years, legacyYear)
+	}
+	sort.Ints(years)
+	return years, nil
+}
+
+func containsInt(nums []int, n int) bool {
+	for _, num := range nums {
+		if num == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// aoc runs the Go solutions of all days.
+//
+// Usage:
+//
+//	aoc <command> [flags] [args]
+//
+// The commands are:
+//
+//	run     solve days by number or all of them
+//	parity  compare the solutions of all languages
+//	bench   time the solutions of all languages
+//	migrate move the days to the YYYY/dayNN layout
+//	watch   rerun a day on changes and compare with the answers
+//	encrypt encrypt the puzzle inputs
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"run", "solve days by number or all of them", runCmd},
+	{"parity", "compare the solutions of all languages", parityCmd},
+	{"bench", "time the solutions of all languages", benchCmd},
+	{"migrate", "move the days to the YYYY/dayNN layout", migrateCmd},
+	{"watch", "rerun a day on changes and compare with the answers", watchCmd},
+	{"encrypt", "encrypt the puzzle inputs", encryptCmd},
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: aoc <command> [flags] [args]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.usage)
+	}
+}
+
+func run() error {
+	if len(os.Args) < 2 {
+		printUsage()
+		return fmt.Errorf("missing argument: command")
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			return c.run(os.Args[2:])
+		}
+	}
+	printUsage()
+	return fmt.Errorf("unknown command '%s'", os.Args[1])
+}
+
+func main() {
+	err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"solver"
+)
+
+func Test_parseDays(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		expected []int
+	}{
+		{nil, []int{2, 7, 8, 9, 12}},
+		{[]string{"all"}, []int{2, 7, 8, 9, 12}},
+		{[]string{"9", "2"}, []int{9, 2}},
+	} {
+		days, err := parseDays(2022, test.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(days, test.expected) {
+			t.Fatalf("got=%v, want=%v", days, test.expected)
+		}
+	}
+
+	for _, args := range [][]string{{"3"}, {"x"}, {"all", "2"}} {
+		_, err := parseDays(2022, args)
+		if err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
+
+func Test_solveFile(t *testing.T) {
+	for _, test := range []struct {
+		day          int
+		part1, part2 string
+	}{
+		{2, "15", "12"},
+		{7, "95437", "24933642"},
+		{8, "21", "8"},
+		{9, "13", "1"},
+		{12, "31", "29"},
+	} {
+		r := solveFile(context.Background(), nil, 2022, test.day, filepath.Join(dayDir("..", 2022, test.day), "sample.txt"), 0)
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if r.part1 != test.part1 || r.part2 != test.part2 {
+			t.Fatalf("day %d: got=%s %s, want=%s %s", test.day, r.part1, r.part2, test.part1, test.part2)
+		}
+	}
+}
+
+func Test_solveFile_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, day := range solver.Days(2022) {
+		r := solveFile(ctx, nil, 2022, day, filepath.Join(dayDir("..", 2022, day), "sample.txt"), 0)
+		if !errors.Is(r.err, context.Canceled) {
+			t.Fatalf("day %d: got=%v, want=%v", day, r.err, context.Canceled)
+		}
+	}
+}
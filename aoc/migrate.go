@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// localReplace matches a replace directive of a go.mod with a local path.
+var localReplace = regexp.MustCompile(`(?m)^(\s*(?:replace\s+)?\S+(?:\s+\S+)?\s+=>\s+)(\.\.?/\S*)[ \t]*$`)
+
+// moves maps the old directories to the new ones.
+type moves map[string]string
+
+// path returns where p is after the moves.
+func (m moves) path(p string) string {
+	for from, to := range m {
+		if p == from {
+			return to
+		}
+		if strings.HasPrefix(p, from+string(filepath.Separator)) {
+			return filepath.Join(to, p[len(from)+1:])
+		}
+	}
+	return p
+}
+
+// rewriteReplaces fixes the local replace paths of a go.mod which is moved
+// from oldDir to newDir and whose replacements might be moved as well.
+func rewriteReplaces(gomod, oldDir, newDir string, m moves) (string, error) {
+	var err error
+	result := localReplace.ReplaceAllStringFunc(gomod, func(line string) string {
+		parts := localReplace.FindStringSubmatch(line)
+		target := m.path(filepath.Join(oldDir, filepath.FromSlash(parts[2])))
+		rel, relErr := filepath.Rel(newDir, target)
+		if relErr != nil {
+			err = relErr
+			return line
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.HasPrefix(rel, "../") {
+			rel = "./" + rel
+		}
+		return parts[1] + rel
+	})
+	return result, err
+}
+
+// findGoMods returns the go.mod files below root.
+func findGoMods(root string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == "target") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "go.mod" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// migrate moves the days in the root directory to the directory of year and
+// fixes the local replace directives of all go.mod files.
+func migrate(root string, year int, dryRun bool, log io.Writer) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, strconv.Itoa(year))
+	m := moves{}
+	days := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !dayDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		to := filepath.Join(target, entry.Name())
+		if _, err := os.Stat(to); err == nil {
+			return fmt.Errorf("%s already exists", to)
+		}
+		from := filepath.Join(root, entry.Name())
+		m[from] = to
+		days = append(days, from)
+	}
+	if len(m) == 0 {
+		return fmt.Errorf("no days to migrate in %s", root)
+	}
+
+	gomods, err := findGoMods(root)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		err = os.MkdirAll(target, 0o755)
+		if err != nil {
+			return err
+		}
+	}
+	for _, from := range days {
+		to := m[from]
+		fmt.Fprintf(log, "move %s to %s\n", from, to)
+		if dryRun {
+			continue
+		}
+		err = os.Rename(from, to)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range gomods {
+		oldDir := filepath.Dir(file)
+		newDir := m.path(oldDir)
+		newFile := filepath.Join(newDir, "go.mod")
+		readFile := newFile
+		if dryRun {
+			readFile = file
+		}
+		data, err := os.ReadFile(readFile)
+		if err != nil {
+			return err
+		}
+		updated, err := rewriteReplaces(string(data), oldDir, newDir, m)
+		if err != nil {
+			return fmt.Errorf("%s: %w", newFile, err)
+		}
+		if updated == string(data) {
+			continue
+		}
+		fmt.Fprintf(log, "update replace directives of %s\n", newFile)
+		if dryRun {
+			continue
+		}
+		err = os.WriteFile(newFile, []byte(updated), 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateCmd(args []string) error {
+	var (
+		root   string
+		year   int
+		dryRun bool
+	)
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", legacyYear, "year of the days in the root directory")
+	fs.BoolVar(&dryRun, "n", false, "only print what would be done")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc migrate [flags]\n\nmoves dayNN in the root directory to YYYY/dayNN\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var err error
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+	err = migrate(root, year, dryRun, os.Stdout)
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		fmt.Println("done, update the README with go run gen_readme.go")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_rewriteReplaces(t *testing.T) {
+	m := moves{
+		"/repo/day02": "/repo/2022/day02",
+	}
+	for _, test := range []struct {
+		name           string
+		gomod          string
+		oldDir, newDir string
+		expected       string
+	}{
+		{
+			name:     "moved module",
+			gomod:    "module day02\n\nreplace solver => ../../solver\n",
+			oldDir:   "/repo/day02/go",
+			newDir:   "/repo/2022/day02/go",
+			expected: "module day02\n\nreplace solver => ../../../solver\n",
+		},
+		{
+			name:     "moved replacement",
+			gomod:    "module aoc\n\nreplace (\n\tday02 => ../day02/go\n\tsolver => ../solver\n)\n",
+			oldDir:   "/repo/aoc",
+			newDir:   "/repo/aoc",
+			expected: "module aoc\n\nreplace (\n\tday02 => ../2022/day02/go\n\tsolver => ../solver\n)\n",
+		},
+		{
+			name:     "version and subdirectory",
+			gomod:    "replace day02 v0.0.0 => ./day02/go\nreplace x => example.com/x v1.0.0\n",
+			oldDir:   "/repo",
+			newDir:   "/repo",
+			expected: "replace day02 v0.0.0 => ./2022/day02/go\nreplace x => example.com/x v1.0.0\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := rewriteReplaces(test.gomod, filepath.FromSlash(test.oldDir), filepath.FromSlash(test.newDir), m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != test.expected {
+				t.Fatalf("got=%q, want=%q", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_migrate(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "day01", "sample.txt"), "1\n")
+	writeTestFile(t, filepath.Join(root, "day01", "sh", "one.sh"), "wc -l\n")
+	writeTestFile(t, filepath.Join(root, "day02", "go", "go.mod"), "module day02\n\nreplace solver => ../../solver\n")
+	writeTestFile(t, filepath.Join(root, "solver", "go.mod"), "module solver\n")
+
+	solutions, err := findSolutions(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(solutions) != 2 || solutions[0].year != 2022 {
+		t.Fatalf("got=%v, want two solutions of 2022", solutions)
+	}
+
+	err = migrate(root, 2022, false, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "day01")); !os.IsNotExist(err) {
+		t.Fatalf("day01 not moved: %v", err)
+	}
+	gomod, err := os.ReadFile(filepath.Join(root, "2022", "day02", "go", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gomod) != "module day02\n\nreplace solver => ../../../solver\n" {
+		t.Fatalf("got=%q", gomod)
+	}
+
+	migrated, err := findSolutions(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 2 || migrated[0].dir != filepath.Join(root, "2022", "day01", "sh") {
+		t.Fatalf("got=%v, want the solutions in 2022/", migrated)
+	}
+	if dir := dayDir(root, 2022, 1); dir != filepath.Join(root, "2022", "day01") {
+		t.Fatalf("got=%s", dir)
+	}
+
+	err = migrate(root, 2022, false, io.Discard)
+	if err == nil {
+		t.Fatal("expected error without days to migrate")
+	}
+}
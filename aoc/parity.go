@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"solver"
+)
+
+// solution is the solution of a day in one language.
+type solution struct {
+	year int
+	day  int
+	lang string
+	dir  string
+}
+
+// name returns the name of the solution like 2022/day08/go.
+func (s solution) name() string {
+	return fmt.Sprintf("%d/day%02d/%s", s.year, s.day, s.lang)
+}
+
+// findSolutions returns the solutions of all years in root ordered by year,
+// day and language.
+func findSolutions(root string) ([]solution, error) {
+	years, err := findYears(root)
+	if err != nil {
+		return nil, err
+	}
+	solutions := []solution{}
+	for _, year := range years {
+		dir := yearDir(root, year)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			m := dayDirPattern.FindStringSubmatch(entry.Name())
+			if m == nil || !entry.IsDir() {
+				continue
+			}
+			day, _ := strconv.Atoi(m[1])
+			langs, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			for _, lang := range langs {
+				if !lang.IsDir() {
+					continue
+				}
+				solutions = append(solutions, solution{
+					year: year,
+					day:  day,
+					lang: lang.Name(),
+					dir:  filepath.Join(dir, entry.Name(), lang.Name()),
+				})
+			}
+		}
+	}
+	sort.Slice(solutions, func(i, j int) bool {
+		a, b := solutions[i], solutions[j]
+		if a.year != b.year {
+			return a.year < b.year
+		}
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		return a.lang < b.lang
+	})
+	return solutions, nil
+}
+
+// selectDays returns the solutions of year, or of all years if year is 0,
+// and of the days given as arguments or all days if there are no arguments.
+func selectDays(solutions []solution, year int, args []string) ([]solution, error) {
+	days := map[int]bool{}
+	for _, arg := range args {
+		day, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day '%s'", arg)
+		}
+		days[day] = true
+	}
+	selected := []solution{}
+	for _, s := range solutions {
+		if year != 0 && s.year != year {
+			continue
+		}
+		if len(days) > 0 && !days[s.day] {
+			continue
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
+
+// program runs a built solution with an input file and returns its output
+// and the resources it used.
+type program func(input string) ([]byte, usage, error)
+
+// toolchain builds the solutions of a language into the directory out.
+type toolchain struct {
+	// the command which has to be installed
+	command string
+	build   func(s solution, out string) (program, error)
+}
+
+var toolchains = map[string]toolchain{
+	"go":   {"go", buildGo},
+	"rust": {"cargo", buildRust},
+	"c":    {"cc", buildC},
+	"sh":   {"sh", buildShell},
+}
+
+// skipError is returned by buildSolution if the solution can not be built
+// on this machine.
+type skipError struct {
+	msg string
+}
+
+func (e *skipError) Error() string {
+	return e.msg
+}
+
+// buildSolution builds s into the directory out with the toolchain of its
+// language.
+func buildSolution(s solution, out string) (program, error) {
+	tc, ok := toolchains[s.lang]
+	if !ok {
+		return nil, &skipError{"unknown language"}
+	}
+	if _, err := exec.LookPath(tc.command); err != nil {
+		return nil, &skipError{tc.command + " not installed"}
+	}
+	prog, err := tc.build(s, out)
+	if err != nil {
+		return nil, fmt.Errorf("build: %w", err)
+	}
+	return prog, nil
+}
+
+func runCommand(cmd *exec.Cmd) ([]byte, usage, error) {
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	start := time.Now()
+	out, err := cmd.Output()
+	u := usage{wall: time.Since(start)}
+	if cmd.ProcessState != nil {
+		u.user = cmd.ProcessState.UserTime()
+		u.sys = cmd.ProcessState.SystemTime()
+		u.maxRSS = maxRSS(cmd.ProcessState)
+	}
+	if err != nil {
+		return out, u, fmt.Errorf("%s: %w: %s", filepath.Base(cmd.Path), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, u, nil
+}
+
+// executable returns the program which runs exe with the input file as
+// argument.
+func executable(exe string) program {
+	return func(input string) ([]byte, usage, error) {
+		return runCommand(exec.Command(exe, input))
+	}
+}
+
+func buildGo(s solution, out string) (program, error) {
+	exe := filepath.Join(out, fmt.Sprintf("%d-day%02d-go", s.year, s.day))
+	cmd := exec.Command("go", "build", "-o", exe, ".")
+	cmd.Dir = s.dir
+	_, _, err := runCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return executable(exe), nil
+}
+
+var cargoName = regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`)
+
+func buildRust(s solution, out string) (program, error) {
+	manifest, err := os.ReadFile(filepath.Join(s.dir, "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+	m := cargoName.FindSubmatch(manifest)
+	if m == nil {
+		return nil, fmt.Errorf("no package name in Cargo.toml")
+	}
+	// dependencies which can not be downloaded, e.g. without network access,
+	// are a problem of the machine and not of the solution
+	cmd := exec.Command("cargo", "fetch", "--quiet")
+	cmd.Dir = s.dir
+	_, _, err = runCommand(cmd)
+	if err != nil {
+		return nil, &skipError{"cargo dependencies not available"}
+	}
+	target := filepath.Join(out, fmt.Sprintf("%d-day%02d-rust", s.year, s.day))
+	cmd = exec.Command("cargo", "build", "--release", "--quiet", "--offline", "--target-dir", target)
+	cmd.Dir = s.dir
+	_, _, err = runCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return executable(filepath.Join(target, "release", string(m[1]))), nil
+}
+
+func buildC(s solution, out string) (program, error) {
+	sources, err := filepath.Glob(filepath.Join(s.dir, "*.c"))
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no C sources")
+	}
+	exe := filepath.Join(out, fmt.Sprintf("%d-day%02d-c", s.year, s.day))
+	_, _, err = runCommand(exec.Command("cc", append([]string{"-O2", "-o", exe}, sources...)...))
+	if err != nil {
+		return nil, err
+	}
+	return executable(exe), nil
+}
+
+// buildShell returns a program which runs the scripts of the solution in
+// alphabetical order with the input on stdin, one script per part.
+func buildShell(s solution, out string) (program, error) {
+	scripts, err := filepath.Glob(filepath.Join(s.dir, "*.sh"))
+	if err != nil {
+		return nil, err
+	}
+	if len(scripts) == 0 {
+		return nil, fmt.Errorf("no scripts")
+	}
+	return func(input string) ([]byte, usage, error) {
+		output := []byte{}
+		total := usage{}
+		for _, script := range scripts {
+			f, err := os.Open(input)
+			if err != nil {
+				return nil, total, err
+			}
+			cmd := exec.Command("sh", script)
+			cmd.Stdin = f
+			out, u, err := runCommand(cmd)
+			f.Close()
+			total.add(u)
+			if err != nil {
+				return nil, total, err
+			}
+			output = append(output, out...)
+		}
+		return output, total, nil
+	}, nil
+}
+
+// parityResult holds the outputs of the languages of a day for one input.
+type parityResult struct {
+	year  int
+	day   int
+	input string
+	// the plaintext input
+	file    string
+	outputs map[string][]string
+	errors  map[string]error
+	skipped []string
+}
+
+// agree returns whether all languages with an output have the same output.
+func (r *parityResult) agree() bool {
+	var first []string
+	for _, output := range r.outputs {
+		if first == nil {
+			first = output
+			continue
+		}
+		if strings.Join(output, "\n") != strings.Join(first, "\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *parityResult) langs() []string {
+	langs := []string{}
+	for lang := range r.outputs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// checkParity builds the solutions and runs them with the inputs of their
+// day. Solutions without an installed toolchain are skipped.
+func checkParity(solutions []solution, k *keys, inputs []string, log io.Writer) ([]*parityResult, error) {
+	out, err := os.MkdirTemp("", "aoc-parity")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+
+	results := []*parityResult{}
+	byDay := map[solver.Day][]*parityResult{}
+	for _, s := range solutions {
+		day := solver.Day{Year: s.year, Day: s.day}
+		if _, ok := byDay[day]; !ok {
+			for _, input := range inputs {
+				file, ok, err := k.resolveInput(filepath.Join(filepath.Dir(s.dir), input), out)
+				if err != nil {
+					fmt.Fprintf(log, "%s: %s\n", s.name(), err)
+				}
+				if !ok {
+					continue
+				}
+				r := &parityResult{
+					year:    s.year,
+					day:     s.day,
+					input:   input,
+					file:    file,
+					outputs: map[string][]string{},
+					errors:  map[string]error{},
+				}
+				byDay[day] = append(byDay[day], r)
+				results = append(results, r)
+			}
+		}
+
+		prog, err := buildSolution(s, out)
+		var skip *skipError
+		if errors.As(err, &skip) {
+			fmt.Fprintf(log, "%s: skipped: %s\n", s.name(), skip)
+			for _, r := range byDay[day] {
+				r.skipped = append(r.skipped, s.lang)
+			}
+			continue
+		}
+		for _, r := range byDay[day] {
+			if err != nil {
+				r.errors[s.lang] = err
+				continue
+			}
+			output, _, err := prog(r.file)
+			if err != nil {
+				r.errors[s.lang] = err
+				continue
+			}
+			r.outputs[s.lang] = outputLines(output)
+		}
+	}
+	return results, nil
+}
+
+func printParity(w io.Writer, results []*parityResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "year\tday\tinput\tlanguages\tresult")
+	for _, r := range results {
+		result := "ok"
+		switch {
+		case len(r.errors) > 0:
+			result = "ERROR"
+		case !r.agree():
+			result = "DISAGREE"
+		case len(r.outputs) < 2:
+			result = "ok (single language)"
+		}
+		if len(r.skipped) > 0 {
+			result += ", skipped " + strings.Join(r.skipped, " ")
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\n", r.year, r.day, r.input, strings.Join(r.langs(), " "), result)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if len(r.errors) == 0 && r.agree() {
+			continue
+		}
+		fmt.Fprintf(w, "\nday %d of %d, %s:\n", r.day, r.year, r.input)
+		for _, lang := range r.langs() {
+			fmt.Fprintf(w, "  %s:\n    %s\n", lang, strings.Join(r.outputs[lang], "\n    "))
+		}
+		for lang, err := range r.errors {
+			fmt.Fprintf(w, "  %s: %s\n", lang, err)
+		}
+	}
+	return nil
+}
+
+func parityCmd(args []string) error {
+	var (
+		root string
+		year int
+	)
+	fs := flag.NewFlagSet("parity", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", 0, "only check the days of `year`, all years by default")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc parity [flags] [day...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var err error
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+	solutions, err := findSolutions(root)
+	if err != nil {
+		return err
+	}
+	solutions, err = selectDays(solutions, year, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	k, err := loadKeys()
+	if err != nil {
+		return err
+	}
+	results, err := checkParity(solutions, k, []string{"sample.txt", "input.txt"}, os.Stderr)
+	if err != nil {
+		return err
+	}
+	err = printParity(os.Stdout, results)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if len(r.errors) > 0 || !r.agree() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d inputs failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, file, content string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(file, []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_checkParity(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "day01", "sample.txt"), "1\n2\n")
+	writeTestFile(t, filepath.Join(root, "day01", "sh", "one.sh"), "wc -l | tr -d ' '\n")
+	writeTestFile(t, filepath.Join(root, "day01", "sh", "two.sh"), "echo 3\n")
+	writeTestFile(t, filepath.Join(root, "day01", "awk", "one.sh"), "awk 'END { print NR }'\n")
+	writeTestFile(t, filepath.Join(root, "day01", "awk", "two.sh"), "echo 3\n")
+	writeTestFile(t, filepath.Join(root, "day02", "sample.txt"), "\n")
+	writeTestFile(t, filepath.Join(root, "day02", "sh", "one.sh"), "echo 1\n")
+	writeTestFile(t, filepath.Join(root, "day02", "awk", "one.sh"), "echo 2\n")
+	writeTestFile(t, filepath.Join(root, "day02", "cobol", "main.cob"), "")
+	writeTestFile(t, filepath.Join(root, "notes", "sh", "one.sh"), "")
+
+	solutions, err := findSolutions(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []solution{
+		{2022, 1, "awk", filepath.Join(root, "day01", "awk")},
+		{2022, 1, "sh", filepath.Join(root, "day01", "sh")},
+		{2022, 2, "awk", filepath.Join(root, "day02", "awk")},
+		{2022, 2, "cobol", filepath.Join(root, "day02", "cobol")},
+		{2022, 2, "sh", filepath.Join(root, "day02", "sh")},
+	}
+	if !reflect.DeepEqual(solutions, expected) {
+		t.Fatalf("got=%v, want=%v", solutions, expected)
+	}
+
+	toolchains["awk"] = toolchain{"sh", buildShell}
+	defer delete(toolchains, "awk")
+	results, err := checkParity(solutions, nil, []string{"sample.txt", "input.txt"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got=%d results, want=2", len(results))
+	}
+	if !results[0].agree() || !reflect.DeepEqual(results[0].outputs["sh"], []string{"2", "3"}) {
+		t.Fatalf("day 1: got=%v, want agreeing outputs 2 3", results[0].outputs)
+	}
+	if results[1].agree() {
+		t.Fatalf("day 2: got=%v, want disagreement", results[1].outputs)
+	}
+	if !reflect.DeepEqual(results[1].skipped, []string{"cobol"}) {
+		t.Fatalf("day 2: got=%v, want=[cobol] skipped", results[1].skipped)
+	}
+}
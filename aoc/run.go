@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"solver"
+)
+
+// parseDays returns the days of year to run. No arguments or "all" selects
+// all registered days.
+func parseDays(year int, args []string) ([]int, error) {
+	if len(args) == 0 || len(args) == 1 && args[0] == "all" {
+		return solver.Days(year), nil
+	}
+	days := []int{}
+	for _, arg := range args {
+		day, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day '%s'", arg)
+		}
+		if _, ok := solver.Lookup(year, day); !ok {
+			return nil, fmt.Errorf("no solver for day %d of %d", day, year)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// latestYear returns the last year with registered days.
+func latestYear() int {
+	years := solver.Years()
+	if len(years) == 0 {
+		return legacyYear
+	}
+	return years[len(years)-1]
+}
+
+type result struct {
+	day          int
+	part1, part2 string
+	duration     time.Duration
+	err          error
+}
+
+// solveFile solves day with the input in file, which might be encrypted.
+// The duration includes reading the file.
+func solveFile(ctx context.Context, k *keys, year, day int, file string, timeout time.Duration) result {
+	r := result{day: day}
+	s, ok := solver.Lookup(year, day)
+	if !ok {
+		r.err = fmt.Errorf("no solver for day %d of %d", day, year)
+		return r
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	input, err := k.readInput(file)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.part1, r.part2, r.err = s.Solve(ctx, bytes.NewReader(input))
+	r.duration = time.Since(start)
+	return r
+}
+
+func printResults(w io.Writer, results []result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "day\tpart 1\tpart 2\ttime")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(tw, "%d\terror: %s\t\t\n", r.day, r.err)
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", r.day, r.part1, r.part2, r.duration.Round(time.Microsecond))
+	}
+	return tw.Flush()
+}
+
+func runCmd(args []string) error {
+	var (
+		root    string
+		year    int
+		input   string
+		timeout time.Duration
+	)
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", 0, "year of the days, the latest year by default")
+	fs.StringVar(&input, "input", "input.txt", "input file in the directory of each day, e.g. sample.txt")
+	fs.DurationVar(&timeout, "timeout", 0, "stop a day after `duration`, 0 means no limit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc run [flags] [all | day...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if year == 0 {
+		year = latestYear()
+	}
+	days, err := parseDays(year, fs.Args())
+	if err != nil {
+		return err
+	}
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+
+	k, err := loadKeys()
+	if err != nil {
+		return err
+	}
+
+	results := []result{}
+	failed := 0
+	for _, day := range days {
+		r := solveFile(context.Background(), k, year, day, filepath.Join(dayDir(root, year, day), input), timeout)
+		if r.err != nil {
+			failed++
+		}
+		results = append(results, r)
+	}
+	err = printResults(os.Stdout, results)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d days failed", failed, len(days))
+	}
+	return nil
+}
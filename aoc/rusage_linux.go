@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// maxRSS returns the peak resident set size of the process in bytes. Linux
+// counts the memory of the runner before the exec as well, so small programs
+// show a few MiB.
+func maxRSS(ps *os.ProcessState) int64 {
+	ru, ok := ps.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// kilobytes on Linux
+	return ru.Maxrss * 1024
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// maxRSS returns 0 as the peak resident set size is not known.
+func maxRSS(ps *os.ProcessState) int64 {
+	return 0
+}
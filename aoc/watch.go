@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBold   = "\x1b[1m"
+)
+
+// watcher waits for changes of the files of a day.
+type watcher interface {
+	// wait returns after a relevant file changed or ctx is done.
+	wait(ctx context.Context) error
+	close() error
+}
+
+// how long to wait for more changes after a change, editors often write a
+// file in several steps
+const debounce = 100 * time.Millisecond
+
+// relevant returns whether a change of the file needs a new run.
+func relevant(path string) bool {
+	switch filepath.Base(path) {
+	case "sample.txt", "input.txt", "input.txt.enc", "answers.txt":
+		return true
+	}
+	switch filepath.Ext(path) {
+	case ".go", ".rs", ".c", ".h", ".sh":
+		return true
+	}
+	return false
+}
+
+// skipDir returns whether the files in a directory are ignored, like the
+// build output of cargo.
+func skipDir(name string) bool {
+	return name == "target" || name == ".git"
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// pollWatcher compares the size and modification time of the relevant files
+// in an interval. It works everywhere, but is slower than inotify.
+type pollWatcher struct {
+	dir      string
+	interval time.Duration
+	last     map[string]fileState
+}
+
+func newPollWatcher(dir string, interval time.Duration) (*pollWatcher, error) {
+	w := &pollWatcher{
+		dir:      dir,
+		interval: interval,
+	}
+	var err error
+	w.last, err = w.snapshot()
+	return w, err
+}
+
+func (w *pollWatcher) snapshot() (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != w.dir && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !relevant(path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[path] = fileState{info.Size(), info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+func (w *pollWatcher) changed(files map[string]fileState) bool {
+	if len(files) != len(w.last) {
+		return true
+	}
+	for path, state := range files {
+		last, ok := w.last[path]
+		if !ok || last.size != state.size || !last.modTime.Equal(state.modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *pollWatcher) wait(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		files, err := w.snapshot()
+		if err != nil {
+			return err
+		}
+		if w.changed(files) {
+			w.last = files
+			return nil
+		}
+	}
+}
+
+func (w *pollWatcher) close() error {
+	return nil
+}
+
+// diffLines returns the lines which differ, the expected ones prefixed with
+// - and the actual ones with +.
+func diffLines(expected, actual []string) []string {
+	diff := []string{}
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		switch {
+		case i >= len(actual):
+			diff = append(diff, "-"+expected[i])
+		case i >= len(expected):
+			diff = append(diff, "+"+actual[i])
+		case expected[i] != actual[i]:
+			diff = append(diff, "-"+expected[i], "+"+actual[i])
+		}
+	}
+	return diff
+}
+
+// checkDay builds the solutions, runs them with the inputs of the day and
+// compares the output with the answers. It returns whether all parts with
+// an answer passed.
+func checkDay(w io.Writer, solutions []solution, k *keys, a *answers, inputs []string, out string) bool {
+	passed := true
+	for _, s := range solutions {
+		fmt.Fprintf(w, "%s%s%s\n", ansiBold, s.name(), ansiReset)
+		prog, err := buildSolution(s, out)
+		var skip *skipError
+		if errors.As(err, &skip) {
+			fmt.Fprintf(w, "  %sskipped: %s%s\n", ansiYellow, skip, ansiReset)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, "  %s%s%s\n", ansiRed, err, ansiReset)
+			passed = false
+			continue
+		}
+
+		for _, input := range inputs {
+			file, ok, err := k.resolveInput(filepath.Join(filepath.Dir(s.dir), input), out)
+			if err != nil {
+				fmt.Fprintf(w, "  %s: %s%s%s\n", input, ansiRed, err, ansiReset)
+				passed = false
+				continue
+			}
+			if !ok {
+				continue
+			}
+			output, u, err := prog(file)
+			if err != nil {
+				fmt.Fprintf(w, "  %s: %s%s%s\n", input, ansiRed, err, ansiReset)
+				passed = false
+				continue
+			}
+			lines := outputLines(output)
+			parts := a.parts(input)
+			if len(parts) == 0 {
+				fmt.Fprintf(w, "  %s: %sno answers%s (%s)\n", input, ansiYellow, ansiReset, u.wall.Round(time.Microsecond))
+				for _, line := range lines {
+					fmt.Fprintf(w, "    %s\n", line)
+				}
+				continue
+			}
+
+			correct := a.check(input, lines)
+			fmt.Fprintf(w, "  %s:", input)
+			for _, part := range parts {
+				if correct[part] {
+					fmt.Fprintf(w, " %spart %d PASS%s", ansiGreen, part, ansiReset)
+					continue
+				}
+				fmt.Fprintf(w, " %spart %d FAIL%s", ansiRed, part, ansiReset)
+				passed = false
+			}
+			fmt.Fprintf(w, " (%s)\n", u.wall.Round(time.Microsecond))
+			expected := []string{}
+			for _, l := range a.lines[input] {
+				expected = append(expected, l.text)
+			}
+			for _, line := range diffLines(expected, lines) {
+				color := ansiGreen
+				if line[0] == '-' {
+					color = ansiRed
+				}
+				fmt.Fprintf(w, "    %s%s%s\n", color, line, ansiReset)
+			}
+		}
+	}
+	return passed
+}
+
+func watchCmd(args []string) error {
+	var (
+		root     string
+		year     int
+		lang     string
+		interval time.Duration
+		poll     bool
+	)
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	fs.StringVar(&root, "root", "", "repository root, found from the working directory by default")
+	fs.IntVar(&year, "year", 0, "year of the day, the latest year by default")
+	fs.StringVar(&lang, "lang", "", "only watch the solution in `language`, e.g. go")
+	fs.DurationVar(&interval, "interval", 500*time.Millisecond, "interval of the polling watcher")
+	fs.BoolVar(&poll, "poll", false, "poll for changes instead of using inotify")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: aoc watch [flags] day\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("missing argument: day")
+	}
+	day, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid day '%s'", fs.Arg(0))
+	}
+	if root == "" {
+		root, err = findRoot()
+		if err != nil {
+			return err
+		}
+	}
+	if year == 0 {
+		years, err := findYears(root)
+		if err != nil {
+			return err
+		}
+		if len(years) == 0 {
+			return fmt.Errorf("no days in %s", root)
+		}
+		year = years[len(years)-1]
+	}
+
+	solutions, err := findSolutions(root)
+	if err != nil {
+		return err
+	}
+	solutions, err = selectDays(solutions, year, []string{fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	if lang != "" {
+		selected := []solution{}
+		for _, s := range solutions {
+			if s.lang == lang {
+				selected = append(selected, s)
+			}
+		}
+		solutions = selected
+	}
+	if len(solutions) == 0 {
+		return fmt.Errorf("no solutions for day %d of %d", day, year)
+	}
+
+	dir := dayDir(root, year, day)
+	out, err := os.MkdirTemp("", "aoc-watch")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(out)
+
+	var wt watcher
+	if poll {
+		wt, err = newPollWatcher(dir, interval)
+	} else {
+		wt, err = newWatcher(dir, interval)
+	}
+	if err != nil {
+		return err
+	}
+	defer wt.close()
+
+	k, err := loadKeys()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for {
+		a, err := readAnswersFile(filepath.Join(dir, "answers.txt"))
+		if errors.Is(err, os.ErrNotExist) {
+			a, err = &answers{}, nil
+		}
+		if err != nil {
+			fmt.Printf("%s%s%s\n", ansiRed, err, ansiReset)
+			a = &answers{}
+		}
+
+		fmt.Printf("\n%s\n", time.Now().Format("15:04:05"))
+		checkDay(os.Stdout, solutions, k, a, []string{"sample.txt", "input.txt"}, out)
+		fmt.Printf("watching %s, press ctrl-c to stop\n", dir)
+
+		err = wt.wait(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotifyWatcher watches the directories of a day with inotify.
+type inotifyWatcher struct {
+	file   *os.File
+	events chan string
+	errs   chan error
+
+	mu   sync.Mutex
+	dirs map[int]string
+}
+
+// newWatcher returns an inotify watcher and falls back to polling if
+// inotify is not available, e.g. if the limit of watches is reached.
+func newWatcher(dir string, interval time.Duration) (watcher, error) {
+	w, err := newInotifyWatcher(dir)
+	if err != nil {
+		return newPollWatcher(dir, interval)
+	}
+	return w, nil
+}
+
+func newInotifyWatcher(dir string) (*inotifyWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	w := &inotifyWatcher{
+		// a non-blocking file uses the poller of the runtime, so a close
+		// stops a read
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan string),
+		errs:   make(chan error, 1),
+		dirs:   map[int]string{},
+	}
+	_, err = w.addTree(dir)
+	if err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	go w.read()
+	return w, nil
+}
+
+// addTree watches dir and its subdirectories. It returns the files in them.
+func (w *inotifyWatcher) addTree(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+		if path != dir && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		wd, err := syscall.InotifyAddWatch(int(w.file.Fd()), path, inotifyMask)
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.dirs[wd] = path
+		w.mu.Unlock()
+		return nil
+	})
+	return files, err
+}
+
+func (w *inotifyWatcher) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			w.errs <- err
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[start:start+int(event.Len)], "\x00"))
+			offset = start + int(event.Len)
+
+			w.mu.Lock()
+			path := filepath.Join(w.dirs[int(event.Wd)], name)
+			w.mu.Unlock()
+			if event.Mask&syscall.IN_ISDIR != 0 {
+				if event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) == 0 || skipDir(name) {
+					continue
+				}
+				// files created before the watch was added have no events,
+				// errors show up as missed changes only
+				files, _ := w.addTree(path)
+				for _, file := range files {
+					w.events <- file
+				}
+				continue
+			}
+			w.events <- path
+		}
+	}
+}
+
+func (w *inotifyWatcher) wait(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-w.errs:
+			return err
+		case path := <-w.events:
+			if !relevant(path) {
+				continue
+			}
+		}
+
+		// take the following changes with this one
+		timer := time.NewTimer(debounce)
+		for {
+			select {
+			case <-w.events:
+				continue
+			case <-timer.C:
+			}
+			break
+		}
+		return nil
+	}
+}
+
+func (w *inotifyWatcher) close() error {
+	return w.file.Close()
+}
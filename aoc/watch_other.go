@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "time"
+
+// newWatcher returns a polling watcher as inotify is only available on Linux.
+func newWatcher(dir string, interval time.Duration) (watcher, error) {
+	return newPollWatcher(dir, interval)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_relevant(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"day01/sample.txt":      true,
+		"day01/answers.txt":     true,
+		"day01/go/main.go":      true,
+		"day04/rust/src/lib.rs": true,
+		"day03/c/main.c":        true,
+		"day01/notes.txt":       false,
+		"day04/rust/Cargo.lock": false,
+		"day02/go/day02":        false,
+	} {
+		if relevant(path) != expected {
+			t.Fatalf("%s: got=%v, want=%v", path, !expected, expected)
+		}
+	}
+}
+
+func Test_diffLines(t *testing.T) {
+	diff := diffLines([]string{"15", "12"}, []string{"15", "13", "1"})
+	expected := []string{"-12", "+13", "+1"}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("got=%v, want=%v", diff, expected)
+	}
+}
+
+func testWatcher(t *testing.T, newWatcher func(dir string) (watcher, error)) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go", "main.go"), "package main\n")
+	w, err := newWatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	// irrelevant changes
+	writeTestFile(t, filepath.Join(dir, "notes.md"), "notes\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err = w.wait(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got=%v, want=%v", err, context.DeadlineExceeded)
+	}
+
+	// a new directory has to be watched as well
+	writeTestFile(t, filepath.Join(dir, "c", "main.c"), "int main() {}\n")
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = w.wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		err := os.WriteFile(filepath.Join(dir, "go", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = w.wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_pollWatcher(t *testing.T) {
+	testWatcher(t, func(dir string) (watcher, error) {
+		return newPollWatcher(dir, 20*time.Millisecond)
+	})
+}
+
+func Test_newWatcher(t *testing.T) {
+	testWatcher(t, func(dir string) (watcher, error) {
+		return newWatcher(dir, 20*time.Millisecond)
+	})
+}
+
+func Test_checkDay(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "day01", "sample.txt"), "1\n2\n")
+	writeTestFile(t, filepath.Join(root, "day01", "input.txt"), "1\n")
+	writeTestFile(t, filepath.Join(root, "day01", "sh", "one.sh"), "wc -l | tr -d ' '\n")
+	writeTestFile(t, filepath.Join(root, "day01", "sh", "two.sh"), "echo 3\n")
+	solutions, err := findSolutions(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := readAnswers(strings.NewReader("sample.txt 1 2\nsample.txt 2 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	passed := checkDay(buf, solutions, nil, a, []string{"sample.txt", "input.txt"}, t.TempDir())
+	if passed {
+		t.Fatal("expected failed part 2")
+	}
+	for _, expected := range []string{
+		ansiGreen + "part 1 PASS",
+		ansiRed + "part 2 FAIL",
+		ansiRed + "-4" + ansiReset,
+		ansiGreen + "+3" + ansiReset,
+		"input.txt: " + ansiYellow + "no answers",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("%q not in output:\n%s", expected, buf.String())
+		}
+	}
+}
@@ -1,3 +1,7 @@
 module day02
 
 go 1.19
+
+require solver v0.0.0
+
+replace solver => ../../solver
@@ -0,0 +1,302 @@
+package solution
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"solver"
+)
+
+// Sign is the index of a sign in the names of the rules, see Rules.Name.
+type Sign int
+
+type Game struct {
+	My       Sign
+	Opponent Sign
+}
+
+type Result int
+
+const (
+	Win Result = iota
+	Draw
+	Lose
+)
+
+func (r Result) String() string {
+	switch r {
+	case Win:
+		return "Win"
+	case Draw:
+		return "Draw"
+	case Lose:
+		return "Lose"
+	default:
+		panic("invalid result")
+	}
+}
+
+func getScores(rounds []Round, scoreFn func(Round) (int, error)) (int, error) {
+	score := 0
+	for _, round := range rounds {
+		points, err := scoreFn(round)
+		if err != nil {
+			return 0, err
+		}
+		score += points
+	}
+	return score, nil
+}
+
+// ParseError is an error in the strategy guide. Lines and columns start at
+// 1.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseErrors are all errors of a strategy guide.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// readGame splits a line of the strategy guide into its symbols separated by
+// white space. It also returns the column of every symbol. Which symbols are
+// needed is up to the mapping.
+func readGame(line string) (Round, []int, error) {
+	round := Round{}
+	columns := []int{}
+	start := -1
+	for i, r := range line + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				round = append(round, line[start:i])
+				columns = append(columns, start+1)
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if len(round) == 0 {
+		return nil, nil, &ParseError{Column: 1, Msg: "empty line"}
+	}
+	return round, columns, nil
+}
+
+type readOptions struct {
+	// skip empty lines instead of failing
+	skipBlank bool
+	// return all errors instead of the first one
+	allErrors bool
+	// check is called for every round to find unknown symbols early
+	check func(Round) error
+	// stop reading with the error of ctx once it is done
+	ctx context.Context
+}
+
+func readGames(in io.Reader, opts readOptions) ([]Round, error) {
+	games := []Round{}
+	errs := ParseErrors{}
+
+	scanner := bufio.NewScanner(in)
+	lineNr := 0
+	for scanner.Scan() {
+		if opts.ctx != nil && opts.ctx.Err() != nil {
+			return nil, opts.ctx.Err()
+		}
+		lineNr++
+		line := scanner.Text()
+		if opts.skipBlank && strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		game, columns, err := readGame(line)
+		if err == nil && opts.check != nil {
+			err = opts.check(game)
+			symbolErr, ok := err.(*symbolError)
+			switch {
+			case ok && symbolErr.index < len(columns):
+				err = &ParseError{Column: columns[symbolErr.index], Msg: symbolErr.msg}
+			case ok:
+				err = &ParseError{Column: len(line) + 1, Msg: symbolErr.msg}
+			case err != nil:
+				err = &ParseError{Column: 1, Msg: err.Error()}
+			}
+		}
+		if err != nil {
+			parseErr := err.(*ParseError)
+			parseErr.Line = lineNr
+			if !opts.allErrors {
+				return nil, parseErr
+			}
+			errs = append(errs, parseErr)
+			continue
+		}
+
+		games = append(games, game)
+	}
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return games, nil
+}
+
+func readGamesFile(file string, opts readOptions) ([]Round, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rounds, err := readGames(f, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return rounds, nil
+}
+
+func Run() error {
+	var (
+		game          string
+		mappingFile   string
+		columns       []string
+		explainFmt    string
+		optimizeGuide bool
+		runs          int
+		seed          int64
+		skipBlank     bool
+		allErrors     bool
+		part          solver.Part
+		format        string
+	)
+	flag.StringVar(&game, "game", "rps", "game to play: rps, rpsls, rps7, rps15 or rps101")
+	flag.StringVar(&mappingFile, "mapping", "", "read the meaning of the columns from `file`")
+	flag.Func("column", "add a mapping line, e.g. 'part3 2 delta X=0 Y=3 Z=6' (repeatable)", func(s string) error {
+		columns = append(columns, s)
+		return nil
+	})
+	flag.StringVar(&explainFmt, "explain", "", "explain every round instead of printing the scores, as text or jsonl")
+	flag.BoolVar(&optimizeGuide, "optimize", false, "compare the guide with the best possible score and other strategies")
+	flag.IntVar(&runs, "runs", 1000, "number of Monte Carlo runs of the random strategy")
+	flag.Int64Var(&seed, "seed", 1, "seed of the random strategy")
+	flag.BoolVar(&skipBlank, "skip-blank", false, "skip empty lines of the strategy guide")
+	flag.BoolVar(&allErrors, "all-errors", false, "report all errors of the strategy guide instead of the first one")
+	flag.Var(&part, "part", "only solve part `n`, i.e. the n-th interpretation of the guide")
+	flag.StringVar(&format, "format", "text", "output format of the scores: text or json")
+	flag.Parse()
+
+	out, err := solver.NewOutput(os.Stdout, 2, format)
+	if err != nil {
+		return err
+	}
+	if out.JSON() && (optimizeGuide || explainFmt != "") {
+		return fmt.Errorf("-format json only prints the scores, not -optimize or -explain")
+	}
+
+	newRules, ok := games[game]
+	if !ok {
+		return fmt.Errorf("unknown game '%s'", game)
+	}
+	m := &mapping{
+		rules: newRules(),
+	}
+
+	if mappingFile != "" {
+		f, err := os.Open(mappingFile)
+		if err != nil {
+			return err
+		}
+		err = m.read(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", mappingFile, err)
+		}
+	}
+	for _, line := range columns {
+		err := m.addLine(line)
+		if err != nil {
+			return fmt.Errorf("-column '%s': %w", line, err)
+		}
+	}
+	if mappingFile == "" && len(columns) == 0 {
+		if !defaultMappingGames[game] {
+			return fmt.Errorf("-game %s needs -mapping or -column, the default mapping is for rps and rpsls only", game)
+		}
+		err := m.read(strings.NewReader(defaultMapping))
+		if err != nil {
+			return err
+		}
+	}
+	err = m.validate()
+	if err != nil {
+		return err
+	}
+	if part != 0 {
+		if int(part) > len(m.interpretations) {
+			return fmt.Errorf("no interpretation for part %d", part)
+		}
+		m.interpretations = m.interpretations[part-1 : part]
+	}
+
+	return out.EachInput(flag.Args(), func(r io.Reader) error {
+		rounds, err := readGames(r, readOptions{
+			skipBlank: skipBlank,
+			allErrors: allErrors,
+			check:     m.check,
+		})
+		if err != nil {
+			return solver.InvalidInput(err)
+		}
+
+		if optimizeGuide {
+			return optimize(os.Stdout, m.rules, m.interpretations, rounds, runs, seed)
+		}
+
+		if explainFmt != "" {
+			return explain(os.Stdout, explainFmt, m.rules, m.interpretations, rounds)
+		}
+
+		for i, in := range m.interpretations {
+			score, err := getScores(rounds, func(round Round) (int, error) {
+				return in.getScore(m.rules, round)
+			})
+			if err != nil {
+				return err
+			}
+			n := i + 1
+			if part != 0 {
+				n = int(part)
+			}
+			err = out.Answer(n, score)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package solution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// roundExplanation shows how the score of a round comes together.
+type roundExplanation struct {
+	Interpretation string   `json:"interpretation"`
+	Round          int      `json:"round"`
+	Symbols        []string `json:"symbols"`
+	Opponent       string   `json:"opponent,omitempty"`
+	My             string   `json:"my,omitempty"`
+	Result         string   `json:"result,omitempty"`
+	SignPoints     int      `json:"sign_points"`
+	ResultPoints   int      `json:"result_points"`
+	Delta          int      `json:"delta"`
+	Score          int      `json:"score"`
+	Total          int      `json:"total"`
+}
+
+type scoredRound struct {
+	Round int `json:"round"`
+	Score int `json:"score"`
+}
+
+type explanationSummary struct {
+	Interpretation string      `json:"interpretation"`
+	Total          int         `json:"total"`
+	Wins           int         `json:"wins"`
+	Draws          int         `json:"draws"`
+	Losses         int         `json:"losses"`
+	Best           scoredRound `json:"best"`
+	Worst          scoredRound `json:"worst"`
+}
+
+// explainRounds explains every round of the interpretation. Rounds are
+// numbered from 1. On a tie the first round is the best or worst one.
+func explainRounds(r *Rules, in *Interpretation, rounds []Round) ([]roundExplanation, explanationSummary, error) {
+	explanations := []roundExplanation{}
+	summary := explanationSummary{
+		Interpretation: in.Name,
+	}
+	for i, round := range rounds {
+		play, err := in.play(r, round)
+		if err != nil {
+			return nil, summary, fmt.Errorf("round %d: %w", i+1, err)
+		}
+		summary.Total += play.Score()
+		e := roundExplanation{
+			Interpretation: in.Name,
+			Round:          i + 1,
+			Symbols:        round,
+			SignPoints:     play.SignPoints,
+			ResultPoints:   play.ResultPoints,
+			Delta:          play.Delta,
+			Score:          play.Score(),
+			Total:          summary.Total,
+		}
+		if play.HasGame {
+			e.Opponent = r.Name(play.Game.Opponent)
+			e.My = r.Name(play.Game.My)
+			e.Result = play.Result.String()
+			switch play.Result {
+			case Win:
+				summary.Wins++
+			case Draw:
+				summary.Draws++
+			case Lose:
+				summary.Losses++
+			}
+		}
+		if i == 0 || e.Score > summary.Best.Score {
+			summary.Best = scoredRound{e.Round, e.Score}
+		}
+		if i == 0 || e.Score < summary.Worst.Score {
+			summary.Worst = scoredRound{e.Round, e.Score}
+		}
+		explanations = append(explanations, e)
+	}
+	return explanations, summary, nil
+}
+
+// explain writes the explanation of all interpretations as text table or as
+// JSON lines (jsonl) with a summary after the rounds of every
+// interpretation.
+func explain(w io.Writer, format string, r *Rules, interpretations []*Interpretation, rounds []Round) error {
+	if format != "text" && format != "jsonl" {
+		return fmt.Errorf("unknown format '%s'", format)
+	}
+	enc := json.NewEncoder(w)
+	for i, in := range interpretations {
+		explanations, summary, err := explainRounds(r, in, rounds)
+		if err != nil {
+			return err
+		}
+
+		if format == "jsonl" {
+			for _, e := range explanations {
+				err = enc.Encode(e)
+				if err != nil {
+					return err
+				}
+			}
+			err = enc.Encode(struct {
+				Summary explanationSummary `json:"summary"`
+			}{summary})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, in.Name)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "round\tsymbols\topponent\tmy\tresult\tsign points\tresult points\tdelta\tscore\ttotal")
+		for _, e := range explanations {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\n",
+				e.Round, strings.Join(e.Symbols, " "), e.Opponent, e.My, e.Result,
+				e.SignPoints, e.ResultPoints, e.Delta, e.Score, e.Total)
+		}
+		err = tw.Flush()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "total=%d, wins=%d, draws=%d, losses=%d, best=round %d (%d), worst=round %d (%d)\n",
+			summary.Total, summary.Wins, summary.Draws, summary.Losses,
+			summary.Best.Round, summary.Best.Score, summary.Worst.Round, summary.Worst.Score)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package solution
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Round is a line of the strategy guide with the symbols as they are
+// written. What the symbols mean is up to an Interpretation.
+type Round []string
+
+type ColumnKind int
+
+const (
+	// the sign the opponent plays
+	OpponentColumn ColumnKind = iota
+	// the sign I play
+	SignColumn
+	// the result I have to get
+	OutcomeColumn
+	// points added to the score
+	DeltaColumn
+)
+
+var columnKinds = map[string]ColumnKind{
+	"opponent": OpponentColumn,
+	"sign":     SignColumn,
+	"outcome":  OutcomeColumn,
+	"delta":    DeltaColumn,
+}
+
+func (k ColumnKind) String() string {
+	for name, kind := range columnKinds {
+		if kind == k {
+			return name
+		}
+	}
+	return fmt.Sprintf("ColumnKind(%d)", int(k))
+}
+
+// Column maps the symbols of one column of the guide to a sign, a result or
+// points depending on its kind.
+type Column struct {
+	Index  int
+	Kind   ColumnKind
+	Values map[string]int
+}
+
+// Interpretation gives a meaning to the columns of the guide.
+type Interpretation struct {
+	Name    string
+	Columns []Column
+}
+
+// symbolError is returned if the symbol at index of a round can not be
+// interpreted.
+type symbolError struct {
+	index int
+	msg   string
+}
+
+func (e *symbolError) Error() string {
+	return e.msg
+}
+
+// Play is a round as understood by an interpretation.
+type Play struct {
+	Game         Game
+	Result       Result
+	SignPoints   int
+	ResultPoints int
+	Delta        int
+	// whether the interpretation contains a game at all or only deltas
+	HasGame bool
+}
+
+func (p Play) Score() int {
+	return p.SignPoints + p.ResultPoints + p.Delta
+}
+
+// play applies the interpretation to a round.
+func (in *Interpretation) play(r *Rules, round Round) (Play, error) {
+	var (
+		play       Play
+		hasOp      bool
+		hasSign    bool
+		hasOutcome bool
+	)
+	for _, col := range in.Columns {
+		if col.Index >= len(round) {
+			return play, &symbolError{col.Index, fmt.Sprintf("%s: column %d missing", in.Name, col.Index+1)}
+		}
+		symbol := round[col.Index]
+		value, ok := col.Values[symbol]
+		if !ok {
+			return play, &symbolError{col.Index, fmt.Sprintf("%s: unknown symbol '%s' in column %d", in.Name, symbol, col.Index+1)}
+		}
+		switch col.Kind {
+		case OpponentColumn:
+			play.Game.Opponent = Sign(value)
+			hasOp = true
+		case SignColumn:
+			play.Game.My = Sign(value)
+			hasSign = true
+		case OutcomeColumn:
+			play.Result = Result(value)
+			hasOutcome = true
+		case DeltaColumn:
+			play.Delta += value
+		}
+	}
+
+	if !hasOp {
+		return play, nil
+	}
+	play.HasGame = true
+	if hasSign {
+		play.Result = r.getResult(play.Game)
+	} else if hasOutcome {
+		play.Game.My = r.getExpectedSign(play.Game.Opponent, play.Result)
+	}
+	play.SignPoints = r.SignPoints[play.Game.My]
+	play.ResultPoints = r.ResultPoints[play.Result]
+	return play, nil
+}
+
+func (in *Interpretation) getScore(r *Rules, round Round) (int, error) {
+	play, err := in.play(r, round)
+	if err != nil {
+		return 0, err
+	}
+	return play.Score(), nil
+}
+
+func (in *Interpretation) validate() error {
+	counts := map[ColumnKind]int{}
+	for _, col := range in.Columns {
+		counts[col.Kind]++
+	}
+	if counts[OpponentColumn] > 1 || counts[SignColumn]+counts[OutcomeColumn] > 1 {
+		return fmt.Errorf("%s: only one opponent and one sign or outcome column allowed", in.Name)
+	}
+	if counts[OpponentColumn] != counts[SignColumn]+counts[OutcomeColumn] {
+		return fmt.Errorf("%s: opponent column needs a sign or outcome column and vice versa", in.Name)
+	}
+	return nil
+}
+
+// A mapping defines interpretations with one line per column:
+//
+//	<interpretation> <column> <kind> <symbol>=<value>...
+//
+// Columns start at 1, the kind is one of opponent, sign, outcome or delta and
+// the values are sign names, Win, Draw or Lose and numbers respectively.
+// Empty lines and lines starting with # are ignored.
+const defaultMapping = `# the interpretations of the puzzle
+part1 1 opponent A=Rock B=Paper C=Scissors
+part1 2 sign X=Rock Y=Paper Z=Scissors
+part2 1 opponent A=Rock B=Paper C=Scissors
+part2 2 outcome X=Lose Y=Draw Z=Win
+`
+
+// defaultMappingGames are the games which are played with defaultMapping if
+// there is no other mapping. The other games have more signs.
+var defaultMappingGames = map[string]bool{"rps": true, "rpsls": true}
+
+// mapping collects the interpretations of a mapping in the order they first
+// appear.
+type mapping struct {
+	rules           *Rules
+	interpretations []*Interpretation
+}
+
+func parseValue(r *Rules, kind ColumnKind, value string) (int, error) {
+	switch kind {
+	case OpponentColumn, SignColumn:
+		for i, name := range r.Names {
+			if strings.EqualFold(name, value) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("unknown sign '%s'", value)
+	case OutcomeColumn:
+		for _, result := range []Result{Win, Draw, Lose} {
+			if strings.EqualFold(result.String(), value) {
+				return int(result), nil
+			}
+		}
+		return 0, fmt.Errorf("unknown outcome '%s'", value)
+	default:
+		return strconv.Atoi(value)
+	}
+}
+
+func (m *mapping) addLine(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return fmt.Errorf("expected '<interpretation> <column> <kind> <symbol>=<value>...'")
+	}
+	index, err := strconv.Atoi(fields[1])
+	if err != nil || index < 1 {
+		return fmt.Errorf("invalid column '%s'", fields[1])
+	}
+	kind, ok := columnKinds[fields[2]]
+	if !ok {
+		return fmt.Errorf("invalid kind '%s'", fields[2])
+	}
+
+	col := Column{
+		Index:  index - 1,
+		Kind:   kind,
+		Values: map[string]int{},
+	}
+	for _, field := range fields[3:] {
+		symbol, value, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("separator not found in '%s'", field)
+		}
+		col.Values[symbol], err = parseValue(m.rules, kind, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	var in *Interpretation
+	for _, i := range m.interpretations {
+		if i.Name == fields[0] {
+			in = i
+		}
+	}
+	if in == nil {
+		in = &Interpretation{Name: fields[0]}
+		m.interpretations = append(m.interpretations, in)
+	}
+	in.Columns = append(in.Columns, col)
+	return nil
+}
+
+func (m *mapping) read(input io.Reader) error {
+	scanner := bufio.NewScanner(input)
+	lineNr := 0
+	for scanner.Scan() {
+		lineNr++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err := m.addLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNr, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (m *mapping) validate() error {
+	if len(m.interpretations) == 0 {
+		return fmt.Errorf("no interpretations")
+	}
+	for _, in := range m.interpretations {
+		err := in.validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns an error if one of the interpretations can not play the round
+// or if the round has symbols in columns no interpretation uses.
+func (m *mapping) check(round Round) error {
+	used := 0
+	for _, in := range m.interpretations {
+		for _, col := range in.Columns {
+			if col.Index >= used {
+				used = col.Index + 1
+			}
+		}
+	}
+	if len(round) > used {
+		return &symbolError{used, fmt.Sprintf("unexpected symbol '%s'", round[used])}
+	}
+	for _, in := range m.interpretations {
+		_, err := in.play(m.rules, round)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
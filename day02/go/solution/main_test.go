@@ -0,0 +1,387 @@
+package solution
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"solver"
+)
+
+func Test_getResult(t *testing.T) {
+	rpsls := games["rpsls"]()
+	index := map[string]Sign{}
+	for i, name := range rpsls.Names {
+		index[name] = Sign(i)
+	}
+	for _, test := range [][2]string{
+		{"Scissors", "Paper"},
+		{"Paper", "Rock"},
+		{"Rock", "Lizard"},
+		{"Lizard", "Spock"},
+		{"Spock", "Scissors"},
+		{"Scissors", "Lizard"},
+		{"Lizard", "Paper"},
+		{"Paper", "Spock"},
+		{"Spock", "Rock"},
+		{"Rock", "Scissors"},
+	} {
+		t.Run(test[0]+"_"+test[1], func(t *testing.T) {
+			game := Game{My: index[test[0]], Opponent: index[test[1]]}
+			if result := rpsls.getResult(game); result != Win {
+				t.Fatalf("got=%s, want=%s", result, Win)
+			}
+			game.My, game.Opponent = game.Opponent, game.My
+			if result := rpsls.getResult(game); result != Lose {
+				t.Fatalf("reversed: got=%s, want=%s", result, Lose)
+			}
+		})
+	}
+}
+
+func Test_games(t *testing.T) {
+	for name, newRules := range games {
+		t.Run(name, func(t *testing.T) {
+			// panics on invalid rules
+			r := newRules()
+			for s := range r.Names {
+				for _, result := range []Result{Win, Draw, Lose} {
+					expected := r.getExpectedSign(Sign(s), result)
+					game := Game{My: expected, Opponent: Sign(s)}
+					if r.getResult(game) != result {
+						t.Fatalf("%s against %s: got=%s, want=%s", r.Name(expected), r.Name(Sign(s)), r.getResult(game), result)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_NewRules_invalid(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		names []string
+		beats map[string][]string
+	}{
+		{"even", []string{"a", "b", "c", "d"}, nil},
+		{"duplicate", []string{"a", "b", "a"}, nil},
+		{"unknown", []string{"a", "b", "c"}, map[string][]string{"a": {"x"}}},
+		{"itself", []string{"a", "b", "c"}, map[string][]string{"a": {"a"}}},
+		{"both", []string{"a", "b", "c"}, map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"a"}}},
+		{"unbalanced", []string{"a", "b", "c"}, map[string][]string{"a": {"b", "c"}, "b": {"c"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewRules(test.names, test.beats)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func Test_getScores(t *testing.T) {
+	rounds, err := readGamesFile("../../sample.txt", readOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		mapping  string
+		expected []int
+	}{
+		{"default", defaultMapping, []int{15, 12}},
+		{
+			name: "delta",
+			mapping: `
+# only count the results
+results 2 delta X=0 Y=3 Z=6
+`,
+			expected: []int{9},
+		},
+		{
+			name: "swapped columns",
+			mapping: `
+swapped 2 opponent X=Rock Y=Paper Z=Scissors
+swapped 1 sign A=Rock B=Paper C=Scissors
+`,
+			expected: []int{15},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			m := &mapping{rules: RPS}
+			err := m.read(strings.NewReader(test.mapping))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = m.validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.interpretations) != len(test.expected) {
+				t.Fatalf("got %d interpretations, want %d", len(m.interpretations), len(test.expected))
+			}
+			for i, in := range m.interpretations {
+				score, err := getScores(rounds, func(round Round) (int, error) {
+					return in.getScore(RPS, round)
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if score != test.expected[i] {
+					t.Fatalf("%s: got=%d, want=%d", in.Name, score, test.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_mapping_invalid(t *testing.T) {
+	for _, test := range []struct {
+		mapping  string
+		expected string
+	}{
+		{"part1 1 opponent", "line 1: expected '<interpretation> <column> <kind> <symbol>=<value>...'"},
+		{"\npart1 0 opponent A=Rock", "line 2: invalid column '0'"},
+		{"part1 1 guess A=Rock", "line 1: invalid kind 'guess'"},
+		{"part1 1 opponent A=Stone", "line 1: unknown sign 'Stone'"},
+		{"part1 1 outcome A=Tie", "line 1: unknown outcome 'Tie'"},
+		{"part1 1 opponent A", "line 1: separator not found in 'A'"},
+	} {
+		t.Run(test.mapping, func(t *testing.T) {
+			m := &mapping{rules: RPS}
+			err := m.read(strings.NewReader(test.mapping))
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got=%v, want=%s", err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_defaultMappingGames(t *testing.T) {
+	for name := range defaultMappingGames {
+		m := &mapping{rules: games[name]()}
+		err := m.read(strings.NewReader(defaultMapping))
+		if err == nil {
+			err = m.validate()
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+	}
+}
+
+func Test_explainRounds(t *testing.T) {
+	rounds, err := readGamesFile("../../sample.txt", readOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mapping{rules: RPS}
+	err = m.read(strings.NewReader(defaultMapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explanations, summary, err := explainRounds(RPS, m.interpretations[1], rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := explanations[len(explanations)-1]
+	expected := roundExplanation{
+		Interpretation: "part2",
+		Round:          3,
+		Symbols:        []string{"C", "Z"},
+		Opponent:       "Scissors",
+		My:             "Rock",
+		Result:         "Win",
+		SignPoints:     1,
+		ResultPoints:   6,
+		Score:          7,
+		Total:          12,
+	}
+	if !reflect.DeepEqual(last, expected) {
+		t.Fatalf("got=%+v, want=%+v", last, expected)
+	}
+	expectedSummary := explanationSummary{
+		Interpretation: "part2",
+		Total:          12,
+		Wins:           1,
+		Draws:          1,
+		Losses:         1,
+		Best:           scoredRound{3, 7},
+		Worst:          scoredRound{2, 1},
+	}
+	if summary != expectedSummary {
+		t.Fatalf("got=%+v, want=%+v", summary, expectedSummary)
+	}
+}
+
+func Test_strategies(t *testing.T) {
+	// the opponent always plays the sign which beats the previous one
+	opponents := []Sign{}
+	for i := 0; i < 300; i++ {
+		opponents = append(opponents, Sign(i%3))
+	}
+
+	best := bestScore(RPS, opponents)
+	// win with Paper, Scissors and Rock
+	if best != 100*(8+9+7) {
+		t.Fatalf("best: got=%d, want=%d", best, 100*(8+9+7))
+	}
+
+	markov := playStrategy(RPS, newMarkovStrategy(RPS), opponents)
+	if markov < best*9/10 {
+		t.Fatalf("markov: got=%d, want at least %d", markov, best*9/10)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	result := monteCarlo(RPS, func() strategy {
+		return &randomStrategy{3, rnd}
+	}, opponents, 200)
+	// two sign points and three result points on average
+	low, high := result.confidence()
+	if low > 1500 || high < 1500 {
+		t.Fatalf("random: 1500 not in %f-%f", low, high)
+	}
+}
+
+func checkDefault(t *testing.T) func(Round) error {
+	m := &mapping{rules: RPS}
+	err := m.read(strings.NewReader(defaultMapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.check
+}
+
+func Test_readGames(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		opts     readOptions
+		expected []Round
+	}{
+		{"sample", "A Y\nB X\nC Z\n", readOptions{}, []Round{{"A", "Y"}, {"B", "X"}, {"C", "Z"}}},
+		{"white space", "  A\tY \nB   X", readOptions{}, []Round{{"A", "Y"}, {"B", "X"}}},
+		{"skip blank", "\nA Y\n  \nB X\n", readOptions{skipBlank: true}, []Round{{"A", "Y"}, {"B", "X"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.opts.check = checkDefault(t)
+			result, err := readGames(strings.NewReader(test.input), test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("got=%v, want=%v", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_readGames_error(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		opts     readOptions
+		expected string
+	}{
+		{"blank", "A Y\n\nB X", readOptions{}, "line 2, column 1: empty line"},
+		{"one symbol", "A Y\nB", readOptions{}, "line 2, column 2: part1: column 2 missing"},
+		{"too many", "A Y Z", readOptions{}, "line 1, column 5: unexpected symbol 'Z'"},
+		{"unknown opponent", "A Y\n D X", readOptions{}, "line 2, column 2: part1: unknown symbol 'D' in column 1"},
+		{"unknown my", "A  W", readOptions{}, "line 1, column 4: part1: unknown symbol 'W' in column 2"},
+		{
+			name:     "all errors",
+			input:    "A Y\nQ X\n\nB\nC Z",
+			opts:     readOptions{allErrors: true},
+			expected: "line 2, column 1: part1: unknown symbol 'Q' in column 1\nline 3, column 1: empty line\nline 4, column 2: part1: column 2 missing",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.opts.check = checkDefault(t)
+			_, err := readGames(strings.NewReader(test.input), test.opts)
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got=%v, want=%s", err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_readGames_columns(t *testing.T) {
+	m := &mapping{rules: RPS}
+	err := m.read(strings.NewReader(`
+# the third column is my sign, the second one a bonus
+three 1 opponent A=Rock B=Paper C=Scissors
+three 3 sign X=Rock Y=Paper Z=Scissors
+three 2 delta P=0 Q=10
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rounds, err := readGames(strings.NewReader("A P Y\nB Q X\nC P Z\n"), readOptions{check: m.check})
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, err := getScores(rounds, func(round Round) (int, error) {
+		return m.interpretations[0].getScore(RPS, round)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 15+10 {
+		t.Fatalf("got=%d, want=%d", score, 15+10)
+	}
+
+	_, err = readGames(strings.NewReader("A P Y\nB Q\n"), readOptions{check: m.check})
+	expected := "line 2, column 4: three: column 3 missing"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("got=%v, want=%s", err, expected)
+	}
+}
+
+func Test_readGames_readError(t *testing.T) {
+	expected := errors.New("read error")
+	_, err := readGames(iotest.ErrReader(expected), readOptions{})
+	if err != expected {
+		t.Fatalf("got=%v, want=%v", err, expected)
+	}
+}
+
+func Test_Solve(t *testing.T) {
+	s, ok := solver.Lookup(2022, 2)
+	if !ok {
+		t.Fatal("day 2 not registered")
+	}
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	part1, part2, err := s.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "15" || part2 != "12" {
+		t.Fatalf("got=%s %s, want=15 12", part1, part2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = s.Solve(ctx, strings.NewReader("A Y\n"))
+	if err != context.Canceled {
+		t.Fatalf("got=%v, want=%v", err, context.Canceled)
+	}
+	_, err = readGames(strings.NewReader("A Y\n"), readOptions{ctx: ctx, allErrors: true})
+	if err != context.Canceled {
+		t.Fatalf("readGames: got=%v, want=%v", err, context.Canceled)
+	}
+}
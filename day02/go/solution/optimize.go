@@ -0,0 +1,205 @@
+package solution
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"text/tabwriter"
+)
+
+// opponentSigns returns the signs of the opponent as read by the first
+// interpretation with an opponent column.
+func opponentSigns(r *Rules, interpretations []*Interpretation, rounds []Round) ([]Sign, error) {
+	for _, in := range interpretations {
+		for _, col := range in.Columns {
+			if col.Kind != OpponentColumn {
+				continue
+			}
+			signs := []Sign{}
+			for i, round := range rounds {
+				play, err := in.play(r, round)
+				if err != nil {
+					return nil, fmt.Errorf("round %d: %w", i+1, err)
+				}
+				signs = append(signs, play.Game.Opponent)
+			}
+			return signs, nil
+		}
+	}
+	return nil, fmt.Errorf("no interpretation with an opponent column")
+}
+
+// bestScore returns the highest possible score against the opponent, which
+// is playing the sign with the highest score in every round.
+func bestScore(r *Rules, opponents []Sign) int {
+	total := 0
+	for _, op := range opponents {
+		best := 0
+		for s := range r.Names {
+			score := r.getScore(Game{My: Sign(s), Opponent: op})
+			if score > best {
+				best = score
+			}
+		}
+		total += best
+	}
+	return total
+}
+
+// strategy plays against an opponent without knowing what the opponent plays
+// in the current round.
+type strategy interface {
+	// next returns the sign to play in the next round
+	next() Sign
+	// observe tells the strategy what the opponent played
+	observe(opponent Sign)
+}
+
+func playStrategy(r *Rules, s strategy, opponents []Sign) int {
+	total := 0
+	for _, op := range opponents {
+		total += r.getScore(Game{My: s.next(), Opponent: op})
+		s.observe(op)
+	}
+	return total
+}
+
+type randomStrategy struct {
+	signs int
+	rnd   *rand.Rand
+}
+
+func (s *randomStrategy) next() Sign {
+	return Sign(s.rnd.Intn(s.signs))
+}
+
+func (s *randomStrategy) observe(Sign) {}
+
+// markovStrategy predicts the next sign of the opponent from what the
+// opponent played after the previous sign so far and plays the sign with the
+// highest expected score against the prediction.
+type markovStrategy struct {
+	rules *Rules
+	// transitions[a][b] counts how often the opponent played b after a,
+	// starting at 1 so unseen transitions are possible
+	transitions [][]int
+	prev        Sign
+	started     bool
+}
+
+func newMarkovStrategy(r *Rules) *markovStrategy {
+	n := len(r.Names)
+	s := &markovStrategy{
+		rules:       r,
+		transitions: make([][]int, n),
+	}
+	for i := range s.transitions {
+		s.transitions[i] = make([]int, n)
+		for j := range s.transitions[i] {
+			s.transitions[i][j] = 1
+		}
+	}
+	return s
+}
+
+func (s *markovStrategy) next() Sign {
+	n := len(s.rules.Names)
+	counts := make([]int, n)
+	if s.started {
+		counts = s.transitions[s.prev]
+	} else {
+		for i := range counts {
+			counts[i] = 1
+		}
+	}
+
+	best := Sign(0)
+	bestScore := -1
+	for my := 0; my < n; my++ {
+		// expected score scaled by the sum of the counts
+		expected := 0
+		for op, count := range counts {
+			expected += count * s.rules.getScore(Game{My: Sign(my), Opponent: Sign(op)})
+		}
+		if expected > bestScore {
+			bestScore = expected
+			best = Sign(my)
+		}
+	}
+	return best
+}
+
+func (s *markovStrategy) observe(opponent Sign) {
+	if s.started {
+		s.transitions[s.prev][opponent]++
+	}
+	s.prev = opponent
+	s.started = true
+}
+
+type monteCarloResult struct {
+	runs   int
+	mean   float64
+	stddev float64
+}
+
+// confidence returns the 95% confidence interval of the mean.
+func (m monteCarloResult) confidence() (float64, float64) {
+	margin := 1.96 * m.stddev / math.Sqrt(float64(m.runs))
+	return m.mean - margin, m.mean + margin
+}
+
+// monteCarlo plays the strategy runs times. Every run gets a new strategy.
+func monteCarlo(r *Rules, newStrategy func() strategy, opponents []Sign, runs int) monteCarloResult {
+	sum, sumSq := 0.0, 0.0
+	for i := 0; i < runs; i++ {
+		score := float64(playStrategy(r, newStrategy(), opponents))
+		sum += score
+		sumSq += score * score
+	}
+	result := monteCarloResult{runs: runs}
+	if runs == 0 {
+		return result
+	}
+	result.mean = sum / float64(runs)
+	if runs > 1 {
+		variance := (sumSq - sum*sum/float64(runs)) / float64(runs-1)
+		result.stddev = math.Sqrt(math.Max(variance, 0))
+	}
+	return result
+}
+
+// optimize compares the scores of the guide with the best possible score
+// and the scores of the strategies.
+func optimize(w io.Writer, r *Rules, interpretations []*Interpretation, rounds []Round, runs int, seed int64) error {
+	if runs < 1 {
+		return fmt.Errorf("invalid number of runs %d", runs)
+	}
+	opponents, err := opponentSigns(r, interpretations, rounds)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "strategy\tscore")
+	for _, in := range interpretations {
+		score, err := getScores(rounds, func(round Round) (int, error) {
+			return in.getScore(r, round)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "guide %s\t%d\n", in.Name, score)
+	}
+	fmt.Fprintf(tw, "best\t%d\n", bestScore(r, opponents))
+	fmt.Fprintf(tw, "markov\t%d\n", playStrategy(r, newMarkovStrategy(r), opponents))
+
+	rnd := rand.New(rand.NewSource(seed))
+	result := monteCarlo(r, func() strategy {
+		return &randomStrategy{len(r.Names), rnd}
+	}, opponents, runs)
+	low, high := result.confidence()
+	fmt.Fprintf(tw, "random\t%.1f (95%% CI %.1f-%.1f, %d runs)\n", result.mean, low, high, result.runs)
+	return tw.Flush()
+}
@@ -0,0 +1,183 @@
+package solution
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Rules describe a cyclic game like Rock Paper Scissors with an odd number of
+// signs where every sign beats half of the other signs.
+type Rules struct {
+	Names []string
+	// beats[a][b] is set if sign a beats sign b
+	beats [][]bool
+
+	// scoring
+	SignPoints   []int
+	ResultPoints map[Result]int
+}
+
+// NewRules returns the rules for the signs and the beats relation, which
+// lists for every sign the signs it beats. The signs get one point for the
+// first sign, two for the second and so on and the results the points of the
+// puzzle.
+func NewRules(names []string, beats map[string][]string) (*Rules, error) {
+	n := len(names)
+	if n < 3 || n%2 == 0 {
+		return nil, fmt.Errorf("invalid number of signs %d: needs to be odd and at least 3", n)
+	}
+
+	index := map[string]int{}
+	for i, name := range names {
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("duplicate sign '%s'", name)
+		}
+		index[name] = i
+	}
+
+	r := &Rules{
+		Names:      names,
+		beats:      make([][]bool, n),
+		SignPoints: make([]int, n),
+		ResultPoints: map[Result]int{
+			Win:  6,
+			Draw: 3,
+			Lose: 0,
+		},
+	}
+	for i := range r.beats {
+		r.beats[i] = make([]bool, n)
+		r.SignPoints[i] = i + 1
+	}
+
+	for winner, losers := range beats {
+		w, ok := index[winner]
+		if !ok {
+			return nil, fmt.Errorf("unknown sign '%s'", winner)
+		}
+		for _, loser := range losers {
+			l, ok := index[loser]
+			if !ok {
+				return nil, fmt.Errorf("unknown sign '%s'", loser)
+			}
+			if w == l {
+				return nil, fmt.Errorf("sign '%s' can not beat itself", winner)
+			}
+			r.beats[w][l] = true
+		}
+	}
+
+	// every pair needs a winner and every sign has to win as often as it
+	// loses, otherwise there is one best sign
+	for a := range names {
+		wins := 0
+		for b := range names {
+			if a == b {
+				continue
+			}
+			if r.beats[a][b] == r.beats[b][a] {
+				return nil, fmt.Errorf("no single winner for '%s' and '%s'", names[a], names[b])
+			}
+			if r.beats[a][b] {
+				wins++
+			}
+		}
+		if wins != (n-1)/2 {
+			return nil, fmt.Errorf("sign '%s' beats %d signs instead of %d", names[a], wins, (n-1)/2)
+		}
+	}
+	return r, nil
+}
+
+// CyclicRules returns the rules where every sign beats the (n-1)/2 signs
+// before it, wrapping around at the start.
+func CyclicRules(names ...string) *Rules {
+	n := len(names)
+	beats := map[string][]string{}
+	for i, name := range names {
+		for k := 1; k <= (n-1)/2; k++ {
+			beats[name] = append(beats[name], names[(i-k+n)%n])
+		}
+	}
+	r, err := NewRules(names, beats)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NumberedRules returns cyclic rules with the signs named 1 to n.
+func NumberedRules(n int) *Rules {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = strconv.Itoa(i + 1)
+	}
+	return CyclicRules(names...)
+}
+
+var (
+	// Rock Paper Scissors of the puzzle, the signs are 0 for Rock, 1 for
+	// Paper and 2 for Scissors.
+	RPS = CyclicRules("Rock", "Paper", "Scissors")
+
+	// games by name
+	games = map[string]func() *Rules{
+		"rps": func() *Rules {
+			return CyclicRules("Rock", "Paper", "Scissors")
+		},
+		"rpsls": func() *Rules {
+			return CyclicRules("Rock", "Spock", "Paper", "Lizard", "Scissors")
+		},
+		// every sign beats the three signs after it in the original listing
+		"rps7": func() *Rules {
+			return CyclicRules("Water", "Air", "Paper", "Sponge", "Scissors", "Fire", "Rock")
+		},
+		"rps15": func() *Rules {
+			return CyclicRules("Gun", "Lightning", "Devil", "Dragon", "Water", "Air", "Paper", "Sponge", "Wolf", "Tree", "Human", "Snake", "Scissors", "Fire", "Rock")
+		},
+		"rps101": func() *Rules {
+			return NumberedRules(101)
+		},
+	}
+)
+
+// Name returns the name of sign s in this game.
+func (r *Rules) Name(s Sign) string {
+	if int(s) < 0 || int(s) >= len(r.Names) {
+		panic(fmt.Sprintf("invalid sign: %d", int(s)))
+	}
+	return r.Names[s]
+}
+
+func (r *Rules) getResult(game Game) Result {
+	switch {
+	case game.My == game.Opponent:
+		return Draw
+	case r.beats[game.My][game.Opponent]:
+		return Win
+	default:
+		return Lose
+	}
+}
+
+func (r *Rules) getScore(game Game) int {
+	result := r.getResult(game)
+	return r.SignPoints[game.My] + r.ResultPoints[result]
+}
+
+// getExpectedSign returns the sign which gets result against sign. If more
+// than one sign does, the one with the most points is used and on a tie the
+// first one.
+func (r *Rules) getExpectedSign(sign Sign, result Result) Sign {
+	expectedSign := Sign(-1)
+	for s := range r.Names {
+		game := Game{My: Sign(s), Opponent: sign}
+		if r.getResult(game) != result {
+			continue
+		}
+		if expectedSign == -1 || r.SignPoints[s] > r.SignPoints[expectedSign] {
+			expectedSign = Sign(s)
+		}
+	}
+	return expectedSign
+}
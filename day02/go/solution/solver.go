@@ -0,0 +1,50 @@
+package solution
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"solver"
+)
+
+func init() {
+	solver.Register(2022, 2, solver.Func(Solve))
+}
+
+// Solve returns the total scores of the guide as read by the puzzle.
+func Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	m := &mapping{
+		rules: RPS,
+	}
+	err := m.read(strings.NewReader(defaultMapping))
+	if err != nil {
+		return "", "", err
+	}
+	rounds, err := readGames(input, readOptions{
+		check: m.check,
+		ctx:   ctx,
+	})
+	if ctx.Err() != nil {
+		return "", "", ctx.Err()
+	}
+	if err != nil {
+		return "", "", solver.InvalidInput(err)
+	}
+
+	scores := []string{}
+	for _, in := range m.interpretations {
+		score, err := getScores(rounds, func(round Round) (int, error) {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return in.getScore(m.rules, round)
+		})
+		if err != nil {
+			return "", "", err
+		}
+		scores = append(scores, strconv.Itoa(score))
+	}
+	return scores[0], scores[1], nil
+}
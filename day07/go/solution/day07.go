@@ -0,0 +1,145 @@
+package solution
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"solver"
+)
+
+func sum(nums []int) int {
+	result := 0
+	for _, num := range nums {
+		result += num
+	}
+	return result
+}
+
+func readDirs(input io.Reader) (map[string]int, error) {
+	scanner := bufio.NewScanner(input)
+	dirs := map[string][]int{}
+	dir := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "$ cd ") {
+			dir = filepath.Join(dir, line[len("$ cd "):])
+			if _, ok := dirs[dir]; !ok {
+				dirs[dir] = []int{}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "$ ls") {
+			continue
+		}
+		if strings.HasPrefix(line, "dir ") {
+			continue
+		}
+
+		// file
+		strSize, _, found := strings.Cut(line, " ")
+		if !found {
+			return nil, fmt.Errorf("invalid line '%s'", line)
+		}
+		size, err := strconv.Atoi(strSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid line '%s': %w", line, err)
+		}
+
+		dirs[dir] = append(dirs[dir], size)
+	}
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+
+	dirsTotal := map[string]int{}
+	for dir, files := range dirs {
+		total := 0
+		// find subdirs of dir
+		for subDir, subDirFiles := range dirs {
+			// is sub dir
+			if len(subDir) > len(dir) && strings.HasPrefix(subDir, dir) {
+				total += sum(subDirFiles)
+			}
+		}
+		total += sum(files)
+
+		dirsTotal[dir] = total
+	}
+	return dirsTotal, nil
+}
+
+func solve(dirs map[string]int) int {
+	result := 0
+	for _, size := range dirs {
+		if size <= 100_000 {
+			result += size
+		}
+	}
+	return result
+}
+
+func solve2(dirs map[string]int) int {
+	fsSpace := 70_000_000
+	updateSize := 30_000_000
+	used := dirs["/"]
+	free := fsSpace - used
+	needed := updateSize - free
+	if needed < 0 {
+		return 0
+	}
+
+	// max int isze
+	minDir := int(^uint(0) >> 1)
+
+	for _, size := range dirs {
+		if size < needed {
+			continue
+		}
+		if size < minDir {
+			minDir = size
+		}
+	}
+	return minDir
+}
+
+func Run() error {
+	var (
+		part   solver.Part
+		format string
+	)
+	flag.Var(&part, "part", "only solve part `n`")
+	flag.StringVar(&format, "format", "text", "output format: text or json")
+	flag.Parse()
+
+	out, err := solver.NewOutput(os.Stdout, 7, format)
+	if err != nil {
+		return err
+	}
+
+	return out.EachInput(flag.Args(), func(r io.Reader) error {
+		dirs, err := readDirs(r)
+		if err != nil {
+			return solver.InvalidInput(err)
+		}
+
+		if part.Has(1) {
+			err = out.Answer(1, solve(dirs))
+			if err != nil {
+				return err
+			}
+		}
+		if part.Has(2) {
+			err = out.Answer(2, solve2(dirs))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+package solution
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"solver"
+)
+
+func init() {
+	solver.Register(2022, 7, solver.Func(Solve))
+}
+
+// Solve returns the sum of the small directories and the size of the
+// directory to delete.
+func Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	dirs, err := readDirs(input)
+	if err != nil {
+		return "", "", solver.InvalidInput(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(solve(dirs)), strconv.Itoa(solve2(dirs)), nil
+}
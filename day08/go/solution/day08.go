@@ -0,0 +1,259 @@
+package solution
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"solver"
+)
+
+type pos struct {
+	x int
+	y int
+}
+
+// edge is a set of forest edges from which a tree can be seen.
+type edge uint8
+
+const (
+	fromTop edge = 1 << iota
+	fromRight
+	fromBottom
+	fromLeft
+)
+
+// visibleFrom returns all trees which are visible from outside the forest
+// together with the edges they are visible from. The observers stand right
+// outside of the edges and are lower than any tree.
+func visibleFrom(input forest) map[pos]edge {
+	visiblePositions := map[pos]edge{}
+	add := func(from pos, dir direction, e edge) {
+		for _, p := range input.visibleAlong(from, -1, dir) {
+			visiblePositions[p] |= e
+		}
+	}
+
+	// rows
+	for y := range input {
+		add(pos{-1, y}, right, fromLeft)
+		add(pos{len(input[y]), y}, left, fromRight)
+	}
+
+	// columns
+	for x := range input[0] {
+		add(pos{x, -1}, down, fromTop)
+		add(pos{x, len(input)}, up, fromBottom)
+	}
+
+	return visiblePositions
+}
+
+func solve1(input forest) int {
+	return len(visibleFrom(input))
+}
+
+// scenicScores returns the scenic score of every tree. The rows are split
+// into bands which are scored by the given number of goroutines. It stops
+// early if ctx is done.
+func scenicScores(ctx context.Context, input forest, workers int) ([][]int, error) {
+	if workers > len(input) {
+		workers = len(input)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	scores := make([][]int, len(input))
+	band := (len(input) + workers - 1) / workers
+	wg := sync.WaitGroup{}
+	for start := 0; start < len(input); start += band {
+		end := start + band
+		if end > len(input) {
+			end = len(input)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for y := start; y < end; y++ {
+				if ctx.Err() != nil {
+					return
+				}
+				row := make([]int, len(input[y]))
+				for x := range input[y] {
+					row[x] = input.scenicScore(pos{x, y}, axes)
+				}
+				scores[y] = row
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// bestSpot returns the position with the highest score. On a tie the first
+// position in reading order wins, independent of how the scores were computed.
+func bestSpot(scores [][]int) (pos, int) {
+	best := pos{}
+	cur := 0
+	for y := range scores {
+		for x, score := range scores[y] {
+			if score > cur {
+				cur = score
+				best = pos{x, y}
+			}
+		}
+	}
+	return best, cur
+}
+
+func solve2(ctx context.Context, input forest, workers int) (int, error) {
+	scores, err := scenicScores(ctx, input, workers)
+	if err != nil {
+		return 0, err
+	}
+	_, score := bestSpot(scores)
+	return score, nil
+}
+
+func parse(input io.Reader) (forest, error) {
+	scanner := bufio.NewScanner(input)
+
+	data := forest{}
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(data) > 0 && len(line) != len(data[0]) {
+			return nil, fmt.Errorf("line %d: width %d, want %d", len(data)+1, len(line), len(data[0]))
+		}
+		row := make([]int, len(line))
+		for i, b := range line {
+			if b < '0' || b > '9' {
+				return nil, fmt.Errorf("line %d, column %d: invalid height %q", len(data)+1, i+1, b)
+			}
+			row[i] = int(b - '0')
+		}
+		data = append(data, row)
+	}
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+	if len(data) == 0 || len(data[0]) == 0 {
+		return nil, fmt.Errorf("empty forest")
+	}
+	return data, nil
+}
+
+func Run() error {
+	var (
+		showMap bool
+		pngFile string
+		csvFile string
+		scale   int
+		top     int
+		compass bool
+		workers int
+		part    solver.Part
+		format  string
+	)
+	flag.BoolVar(&showMap, "map", false, "print a colored map of the visible trees")
+	flag.StringVar(&pngFile, "png", "", "write a heatmap of the scenic scores to `file`")
+	flag.StringVar(&csvFile, "csv", "", "write the visibility and scenic score of every tree to `file`")
+	flag.IntVar(&scale, "scale", 8, "pixels per tree in the heatmap")
+	flag.IntVar(&top, "top", 0, "print the `k` most scenic spots")
+	flag.IntVar(&workers, "workers", runtime.GOMAXPROCS(0), "number of goroutines computing the scenic scores")
+	flag.BoolVar(&compass, "compass", false, "look in all eight compass directions for -top")
+	flag.Var(&part, "part", "only solve part `n`")
+	flag.StringVar(&format, "format", "text", "output format of the answers: text or json")
+	flag.Parse()
+
+	out, err := solver.NewOutput(os.Stdout, 8, format)
+	if err != nil {
+		return err
+	}
+	if out.JSON() && (showMap || top > 0) {
+		return fmt.Errorf("-format json can not be combined with -map or -top")
+	}
+
+	if flag.NArg() > 1 && (pngFile != "" || csvFile != "") {
+		return fmt.Errorf("-png and -csv need a single input file")
+	}
+
+	return out.EachInput(flag.Args(), func(r io.Reader) error {
+		data, err := parse(r)
+		if err != nil {
+			return solver.InvalidInput(err)
+		}
+
+		visible := visibleFrom(data)
+		if part.Has(1) {
+			err = out.Answer(1, len(visible))
+			if err != nil {
+				return err
+			}
+		}
+
+		var (
+			scores [][]int
+			best   pos
+		)
+		if part.Has(2) || showMap || pngFile != "" || csvFile != "" {
+			scores, err = scenicScores(context.Background(), data, workers)
+			if err != nil {
+				return err
+			}
+			var result2 int
+			best, result2 = bestSpot(scores)
+			if part.Has(2) {
+				err = out.Answer(2, result2)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if top > 0 {
+			dirs := axes
+			if compass {
+				dirs = compassDirections
+			}
+			for _, spot := range data.topScenic(top, dirs) {
+				fmt.Printf("x=%d, y=%d, score=%d\n", spot.pos.x, spot.pos.y, spot.score)
+			}
+		}
+
+		if showMap {
+			err = printVisibility(os.Stdout, data, visible, best)
+			if err != nil {
+				return err
+			}
+		}
+
+		if pngFile != "" {
+			err = writeFile(pngFile, func(w io.Writer) error {
+				return writeHeatmap(w, scores, best, scale)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if csvFile != "" {
+			err = writeFile(csvFile, func(w io.Writer) error {
+				return writeScores(w, data, visible, scores)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
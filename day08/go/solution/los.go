@@ -0,0 +1,189 @@
+package solution
+
+import (
+	"sort"
+)
+
+// direction is the step of a ray through the forest. Besides the compass
+// directions any rational slope can be used, e.g. direction{2, 1} walks two
+// trees to the right for every tree down.
+type direction struct {
+	dx int
+	dy int
+}
+
+var (
+	up        = direction{0, -1}
+	upRight   = direction{1, -1}
+	right     = direction{1, 0}
+	downRight = direction{1, 1}
+	down      = direction{0, 1}
+	downLeft  = direction{-1, 1}
+	left      = direction{-1, 0}
+	upLeft    = direction{-1, -1}
+
+	// the four directions of the puzzle
+	axes = []direction{up, right, down, left}
+
+	compassDirections = []direction{up, upRight, right, downRight, down, downLeft, left, upLeft}
+)
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ray walks the cells of a Bresenham line from a start position. Since the
+// error term is periodic the line is continued infinitely.
+type ray struct {
+	pos pos
+	dx  int
+	dy  int
+	sx  int
+	sy  int
+	err int
+}
+
+func newRay(from pos, dir direction) *ray {
+	if dir.dx == 0 && dir.dy == 0 {
+		panic("invalid direction")
+	}
+	dx := abs(dir.dx)
+	dy := -abs(dir.dy)
+	return &ray{
+		pos: from,
+		dx:  dx,
+		dy:  dy,
+		sx:  sign(dir.dx),
+		sy:  sign(dir.dy),
+		err: dx + dy,
+	}
+}
+
+func (r *ray) next() pos {
+	e2 := 2 * r.err
+	if e2 >= r.dy {
+		r.err += r.dy
+		r.pos.x += r.sx
+	}
+	if e2 <= r.dx {
+		r.err += r.dx
+		r.pos.y += r.sy
+	}
+	return r.pos
+}
+
+type forest [][]int
+
+func (f forest) inside(p pos) bool {
+	return p.y >= 0 && p.y < len(f) && p.x >= 0 && p.x < len(f[p.y])
+}
+
+func (f forest) height(p pos) int {
+	return f[p.y][p.x]
+}
+
+// walk calls fn for every tree on the ray from an observer at from until fn
+// returns false or the ray leaves the forest. The observer is not part of the
+// ray, so an observer right outside of an edge looks into the forest.
+func (f forest) walk(from pos, dir direction, fn func(p pos, height int) bool) {
+	r := newRay(from, dir)
+	for {
+		p := r.next()
+		if !f.inside(p) {
+			return
+		}
+		if !fn(p, f.height(p)) {
+			return
+		}
+	}
+}
+
+// viewDistance returns the number of trees an observer of the given height at
+// from sees in direction dir. The view ends at the edge or at the first tree
+// which is at least as tall as the observer.
+func (f forest) viewDistance(from pos, height int, dir direction) int {
+	dist := 0
+	f.walk(from, dir, func(_ pos, v int) bool {
+		dist++
+		return v < height
+	})
+	return dist
+}
+
+// visibleAlong returns the trees an observer of the given height at from
+// sees in direction dir. The observer looks over all trees lower than
+// themselves and a tree is visible if no tree in between is at least as tall
+// as the observer and the tree itself.
+func (f forest) visibleAlong(from pos, height int, dir direction) []pos {
+	visible := []pos{}
+	max := -1
+	f.walk(from, dir, func(p pos, v int) bool {
+		if max < height || max < v {
+			visible = append(visible, p)
+		}
+		if v > max {
+			max = v
+		}
+		return true
+	})
+	return visible
+}
+
+// visibleTrees returns all trees an observer sees along the given directions.
+func (f forest) visibleTrees(from pos, height int, dirs []direction) map[pos]struct{} {
+	visible := map[pos]struct{}{}
+	for _, dir := range dirs {
+		for _, p := range f.visibleAlong(from, height, dir) {
+			visible[p] = struct{}{}
+		}
+	}
+	return visible
+}
+
+// scenicScore multiplies the viewing distances from the top of the tree at p
+// along the given directions.
+func (f forest) scenicScore(p pos, dirs []direction) int {
+	score := 1
+	for _, dir := range dirs {
+		score *= f.viewDistance(p, f.height(p), dir)
+	}
+	return score
+}
+
+type spot struct {
+	pos   pos
+	score int
+}
+
+// topScenic returns the k trees with the highest scenic score along the given
+// directions ordered by score. Ties are ordered by position in reading order.
+func (f forest) topScenic(k int, dirs []direction) []spot {
+	spots := []spot{}
+	for y := range f {
+		for x := range f[y] {
+			p := pos{x, y}
+			spots = append(spots, spot{p, f.scenicScore(p, dirs)})
+		}
+	}
+	sort.SliceStable(spots, func(i, j int) bool {
+		return spots[i].score > spots[j].score
+	})
+	if k < len(spots) {
+		spots = spots[:k]
+	}
+	return spots
+}
@@ -0,0 +1,379 @@
+package solution
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"solver"
+)
+
+func readSample(t testing.TB) forest {
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func Test_ray(t *testing.T) {
+	for _, test := range []struct {
+		dir      direction
+		expected []pos
+	}{
+		{right, []pos{{1, 0}, {2, 0}, {3, 0}}},
+		{up, []pos{{0, -1}, {0, -2}, {0, -3}}},
+		{downLeft, []pos{{-1, 1}, {-2, 2}, {-3, 3}}},
+		{direction{2, 1}, []pos{{1, 1}, {2, 1}, {3, 2}, {4, 2}}},
+		{direction{-1, -3}, []pos{{0, -1}, {-1, -2}, {-1, -3}, {-1, -4}, {-2, -5}, {-2, -6}}},
+	} {
+		t.Run(fmt.Sprintf("%d_%d", test.dir.dx, test.dir.dy), func(t *testing.T) {
+			r := newRay(pos{}, test.dir)
+			result := []pos{}
+			for range test.expected {
+				result = append(result, r.next())
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("got=%v, want=%v", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_viewDistance(t *testing.T) {
+	data := readSample(t)
+	for _, test := range []struct {
+		from     pos
+		height   int
+		dir      direction
+		expected int
+	}{
+		{pos{2, 3}, 5, up, 2},
+		{pos{2, 3}, 5, left, 2},
+		{pos{2, 3}, 5, down, 1},
+		{pos{2, 3}, 5, right, 2},
+		{pos{2, 3}, 5, upLeft, 1},
+		{pos{2, 3}, 5, upRight, 2},
+		{pos{2, 3}, 9, up, 3},
+		{pos{0, 0}, 3, up, 0},
+	} {
+		t.Run(fmt.Sprintf("%v_%d_%v", test.from, test.height, test.dir), func(t *testing.T) {
+			dist := data.viewDistance(test.from, test.height, test.dir)
+			if dist != test.expected {
+				t.Fatalf("got=%d, want=%d", dist, test.expected)
+			}
+		})
+	}
+}
+
+func Test_visibleAlong(t *testing.T) {
+	data := readSample(t)
+	for _, test := range []struct {
+		name     string
+		from     pos
+		height   int
+		dir      direction
+		expected []pos
+	}{
+		{"edge", pos{-1, 0}, -1, right, []pos{{0, 0}, {3, 0}}},
+		{"tall observer", pos{-1, 0}, 5, right, []pos{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		{"inside", pos{0, 2}, 1, right, []pos{{1, 2}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			result := data.visibleAlong(test.from, test.height, test.dir)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("got=%v, want=%v", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_topScenic(t *testing.T) {
+	data := readSample(t)
+	expected := []spot{{pos{2, 3}, 8}, {pos{1, 2}, 6}}
+	result := data.topScenic(2, axes)
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("got=%v, want=%v", result, expected)
+	}
+}
+
+func Test_solve(t *testing.T) {
+	data := readSample(t)
+	if result := solve1(data); result != 21 {
+		t.Fatalf("solve1: got=%d, want=21", result)
+	}
+	result, err := solve2(context.Background(), data, 1)
+	if err != nil || result != 8 {
+		t.Fatalf("solve2: got=%d, %v, want=8", result, err)
+	}
+}
+
+func Test_Solve(t *testing.T) {
+	s, ok := solver.Lookup(2022, 8)
+	if !ok {
+		t.Fatal("day 8 not registered")
+	}
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	part1, part2, err := s.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "21" || part2 != "8" {
+		t.Fatalf("got=%s %s, want=21 8", part1, part2)
+	}
+}
+
+func Test_parse_error(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "empty forest"},
+		{"empty line", "\n", "empty forest"},
+		{"letter", "123\n1a3\n", "line 2, column 2: invalid height 'a'"},
+		{"carriage return", "123\n1\r3\n", "line 2, column 2: invalid height '\\r'"},
+		{"ragged", "123\n12\n", "line 2: width 2, want 3"},
+		{"blank line", "123\n\n123\n", "line 2: width 0, want 3"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parse(strings.NewReader(test.input))
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got=%v, want=%s", err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_parse_crlf(t *testing.T) {
+	data, err := parse(strings.NewReader("30373\r\n25512\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := forest{{3, 0, 3, 7, 3}, {2, 5, 5, 1, 2}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("got=%v, want=%v", data, expected)
+	}
+}
+
+func Test_Solve_invalid(t *testing.T) {
+	s, _ := solver.Lookup(2022, 8)
+	_, _, err := s.Solve(context.Background(), strings.NewReader("ab\n"))
+	if code := solver.ExitCode(err); code != solver.ExitInput {
+		t.Fatalf("got=%d (%v), want=%d", code, err, solver.ExitInput)
+	}
+}
+
+func sampleScores(t *testing.T) (forest, map[pos]edge, [][]int, pos) {
+	data := readSample(t)
+	scores, err := scenicScores(context.Background(), data, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best, _ := bestSpot(scores)
+	return data, visibleFrom(data), scores, best
+}
+
+func Test_writeScores(t *testing.T) {
+	data, visible, scores, _ := sampleScores(t)
+	buf := &bytes.Buffer{}
+	err := writeScores(buf, data, visible, scores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1+5*5 {
+		t.Fatalf("got %d records, want %d", len(records), 1+5*5)
+	}
+	for _, expected := range [][]string{
+		{"x", "y", "height", "visible_from", "scenic_score"},
+		{"0", "0", "3", "TL", "0"},
+		{"1", "1", "5", "TL", "1"},
+		{"2", "2", "3", "", "1"},
+		{"2", "3", "5", "BL", "8"},
+		{"0", "2", "6", "TRBL", "0"},
+		{"4", "4", "0", "RB", "0"},
+	} {
+		i := 0
+		if expected[0] != "x" {
+			x, _ := strconv.Atoi(expected[0])
+			y, _ := strconv.Atoi(expected[1])
+			i = 1 + y*5 + x
+		}
+		if !reflect.DeepEqual(records[i], expected) {
+			t.Fatalf("record %d: got=%v, want=%v", i, records[i], expected)
+		}
+	}
+}
+
+func Test_heatmap(t *testing.T) {
+	_, _, scores, best := sampleScores(t)
+	if best != (pos{2, 3}) {
+		t.Fatalf("best: got=%v, want=%v", best, pos{2, 3})
+	}
+	scale := 4
+	img := heatmap(scores, best, scale)
+	if img.Bounds() != image.Rect(0, 0, 5*scale, 5*scale) {
+		t.Fatalf("bounds: got=%v", img.Bounds())
+	}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	for _, test := range []struct {
+		x, y     int
+		expected color.RGBA
+	}{
+		// the frame and the inside of the best spot
+		{best.x * scale, best.y * scale, red},
+		{best.x*scale + scale - 1, best.y*scale + scale - 1, red},
+		{best.x*scale + 1, best.y*scale + 1, heatColors[len(heatColors)-1]},
+		// a tree with score 0
+		{0, 0, heatColors[0]},
+	} {
+		if c := img.RGBAAt(test.x, test.y); c != test.expected {
+			t.Fatalf("pixel %d,%d: got=%v, want=%v", test.x, test.y, c, test.expected)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err := writeHeatmap(buf, scores, best, scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.RGBAModel.Convert(decoded.At(best.x*scale, best.y*scale)); c != red {
+		t.Fatalf("png: got=%v, want=%v", c, red)
+	}
+}
+
+func Test_printVisibility(t *testing.T) {
+	data, visible, _, best := sampleScores(t)
+	buf := &strings.Builder{}
+	err := printVisibility(buf, data, visible, best)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 5+2 {
+		t.Fatalf("got %d lines, want %d", len(lines), 5+2)
+	}
+	// the top left tree is visible from the top and the left
+	if prefix := edgeColors[fromTop|fromLeft] + "3" + ansiReset; !strings.HasPrefix(lines[0], prefix) {
+		t.Fatalf("got=%q, want prefix %q", lines[0], prefix)
+	}
+	// every set of edges has its own color
+	colors := map[string]bool{}
+	for _, c := range edgeColors {
+		colors[c] = true
+	}
+	if len(colors) != len(edgeColors) {
+		t.Fatalf("got %d colors, want %d", len(colors), len(edgeColors))
+	}
+	top := edgeColors[fromTop] + "0" + ansiReset
+	left := edgeColors[fromLeft] + "2" + ansiReset
+	if !strings.Contains(lines[0], top) || !strings.HasPrefix(lines[1], left) {
+		t.Fatalf("got=%q %q, want %q and %q", lines[0], lines[1], top, left)
+	}
+	// the best spot is inverted
+	if spot := edgeColors[fromBottom|fromLeft] + ansiInverse + "5" + ansiReset; !strings.Contains(lines[3], spot) {
+		t.Fatalf("got=%q, want %q", lines[3], spot)
+	}
+	for _, legend := range []string{
+		edgeColors[0] + "-" + ansiReset,
+		edgeColors[fromBottom|fromLeft] + "BL" + ansiReset,
+		edgeColors[fromTop|fromRight|fromBottom|fromLeft] + "TRBL" + ansiReset,
+	} {
+		if !strings.Contains(lines[5], legend) {
+			t.Fatalf("legend: got=%q, want %q", lines[5], legend)
+		}
+	}
+	if !strings.HasSuffix(lines[5], "best spot: "+ansiInverse+"x=2, y=3"+ansiReset) {
+		t.Fatalf("got=%q", lines[5])
+	}
+}
+
+func generateForest(w, h int, seed int64) forest {
+	rnd := rand.New(rand.NewSource(seed))
+	data := make(forest, h)
+	for y := range data {
+		data[y] = make([]int, w)
+		for x := range data[y] {
+			data[y][x] = rnd.Intn(10)
+		}
+	}
+	return data
+}
+
+func Test_scenicScores_parallel(t *testing.T) {
+	data := generateForest(97, 103, 1)
+	expected, err := scenicScores(context.Background(), data, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPos, expectedScore := bestSpot(expected)
+	for _, workers := range []int{0, 2, 3, 8, 200} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			result, err := scenicScores(context.Background(), data, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Fatal("scores differ from single goroutine run")
+			}
+			p, score := bestSpot(result)
+			if p != expectedPos || score != expectedScore {
+				t.Fatalf("got=%v %d, want=%v %d", p, score, expectedPos, expectedScore)
+			}
+		})
+	}
+}
+
+func Test_scenicScores_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := scenicScores(ctx, generateForest(10, 10, 1), 2)
+	if err != context.Canceled {
+		t.Fatalf("got=%v, want=%v", err, context.Canceled)
+	}
+}
+
+func Benchmark_solve2(b *testing.B) {
+	data := generateForest(1000, 1000, 1)
+	for _, bench := range []struct {
+		name    string
+		workers int
+	}{
+		{"single", 1},
+		{"gomaxprocs", runtime.GOMAXPROCS(0)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				solve2(context.Background(), data, bench.workers)
+			}
+		})
+	}
+}
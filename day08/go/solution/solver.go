@@ -0,0 +1,28 @@
+package solution
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"strconv"
+
+	"solver"
+)
+
+func init() {
+	solver.Register(2022, 8, solver.Func(Solve))
+}
+
+// Solve returns the number of visible trees and the highest scenic score.
+func Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	data, err := parse(input)
+	if err != nil {
+		return "", "", solver.InvalidInput(err)
+	}
+	part1 := solve1(data)
+	part2, err := solve2(ctx, data, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(part1), strconv.Itoa(part2), nil
+}
@@ -0,0 +1,201 @@
+package solution
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiInverse = "\x1b[7m"
+)
+
+// colors by the set of edges a tree is visible from, indexed by edge
+var edgeColors = [16]string{
+	"\x1b[90m",       // hidden
+	"\x1b[31m",       // T
+	"\x1b[32m",       // R
+	"\x1b[38;5;208m", // TR
+	"\x1b[34m",       // B
+	"\x1b[35m",       // TB
+	"\x1b[36m",       // RB
+	"\x1b[38;5;130m", // TRB
+	"\x1b[33m",       // L
+	"\x1b[38;5;199m", // TL
+	"\x1b[38;5;118m", // RL
+	"\x1b[38;5;220m", // TRL
+	"\x1b[38;5;63m",  // BL
+	"\x1b[38;5;141m", // TBL
+	"\x1b[38;5;51m",  // RBL
+	"\x1b[1;37m",     // TRBL
+}
+
+// String returns the edges as a combination of the letters T, R, B and L.
+func (e edge) String() string {
+	s := ""
+	for i, c := range "TRBL" {
+		if e&(1<<i) != 0 {
+			s += string(c)
+		}
+	}
+	return s
+}
+
+// printVisibility prints the forest with every tree colored by the set of
+// edges it is visible from. The tree at best is printed inverted.
+func printVisibility(w io.Writer, input [][]int, visible map[pos]edge, best pos) error {
+	bw := bufio.NewWriter(w)
+	for y := range input {
+		for x, height := range input[y] {
+			p := pos{x, y}
+			bw.WriteString(edgeColors[visible[p]])
+			if p == best {
+				bw.WriteString(ansiInverse)
+			}
+			bw.WriteByte(byte('0' + height))
+			bw.WriteString(ansiReset)
+		}
+		bw.WriteByte('\n')
+	}
+
+	bw.WriteString("visible from:")
+	for e, c := range edgeColors {
+		name := edge(e).String()
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(bw, " %s%s%s", c, name, ansiReset)
+	}
+	fmt.Fprintf(bw, ", best spot: %sx=%d, y=%d%s\n", ansiInverse, best.x, best.y, ansiReset)
+	return bw.Flush()
+}
+
+// heatmap gradient from low to high scores
+var heatColors = []color.RGBA{
+	{0x44, 0x01, 0x54, 0xff},
+	{0x3b, 0x52, 0x8b, 0xff},
+	{0x21, 0x91, 0x8c, 0xff},
+	{0x5e, 0xc9, 0x62, 0xff},
+	{0xfd, 0xe7, 0x25, 0xff},
+}
+
+func heatColor(t float64) color.RGBA {
+	if t <= 0 {
+		return heatColors[0]
+	}
+	if t >= 1 {
+		return heatColors[len(heatColors)-1]
+	}
+	t *= float64(len(heatColors) - 1)
+	i := int(t)
+	frac := t - float64(i)
+	from, to := heatColors[i], heatColors[i+1]
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*frac)
+	}
+	return color.RGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), 0xff}
+}
+
+// heatmap renders the scenic scores with scale pixels per tree and a red frame
+// around the best spot. The scores are drawn on a square root scale since a
+// few trees usually have much higher scores than all others.
+func heatmap(scores [][]int, best pos, scale int) *image.RGBA {
+	if scale < 1 {
+		scale = 1
+	}
+	height := len(scores)
+	width := 0
+	max := 0
+	for y := range scores {
+		if len(scores[y]) > width {
+			width = len(scores[y])
+		}
+		for _, score := range scores[y] {
+			if score > max {
+				max = score
+			}
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, height*scale))
+	for y := range scores {
+		for x, score := range scores[y] {
+			t := 0.0
+			if max > 0 {
+				t = math.Sqrt(float64(score) / float64(max))
+			}
+			c := heatColor(t)
+			for py := y * scale; py < (y+1)*scale; py++ {
+				for px := x * scale; px < (x+1)*scale; px++ {
+					img.SetRGBA(px, py, c)
+				}
+			}
+		}
+	}
+
+	// mark best spot
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	frame := scale / 4
+	if frame < 1 {
+		frame = 1
+	}
+	for py := best.y * scale; py < (best.y+1)*scale; py++ {
+		for px := best.x * scale; px < (best.x+1)*scale; px++ {
+			if px-best.x*scale < frame || (best.x+1)*scale-px <= frame ||
+				py-best.y*scale < frame || (best.y+1)*scale-py <= frame {
+				img.SetRGBA(px, py, red)
+			}
+		}
+	}
+	return img
+}
+
+func writeHeatmap(w io.Writer, scores [][]int, best pos, scale int) error {
+	return png.Encode(w, heatmap(scores, best, scale))
+}
+
+// writeScores writes one CSV record per tree.
+func writeScores(w io.Writer, input [][]int, visible map[pos]edge, scores [][]int) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"x", "y", "height", "visible_from", "scenic_score"})
+	if err != nil {
+		return err
+	}
+	for y := range input {
+		for x, height := range input[y] {
+			err = cw.Write([]string{
+				strconv.Itoa(x),
+				strconv.Itoa(y),
+				strconv.Itoa(height),
+				visible[pos{x, y}].String(),
+				strconv.Itoa(scores[y][x]),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,341 @@
+package solution
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"solver"
+)
+
+type direction int
+
+const (
+	UP direction = iota
+	RIGHT
+	DOWN
+	LEFT
+	UP_RIGHT
+	DOWN_RIGHT
+	DOWN_LEFT
+	UP_LEFT
+)
+
+func strToDir(input string) (direction, error) {
+	switch input {
+	case "U":
+		return UP, nil
+	case "D":
+		return DOWN, nil
+	case "L":
+		return LEFT, nil
+	case "R":
+		return RIGHT, nil
+	case "UR":
+		return UP_RIGHT, nil
+	case "DR":
+		return DOWN_RIGHT, nil
+	case "DL":
+		return DOWN_LEFT, nil
+	case "UL":
+		return UP_LEFT, nil
+	default:
+		return -1, fmt.Errorf("invalid direction '%s'", input)
+	}
+}
+
+type command struct {
+	dir  direction
+	dist int
+}
+
+// parse reads a motion script, see script.go.
+func parse(input io.Reader) ([]command, error) {
+	return readCommands(newCommandStream(input))
+}
+
+// readCommands returns all commands of stream.
+func readCommands(stream *commandStream) ([]command, error) {
+	cmds := []command{}
+	for {
+		cmd, ok, err := stream.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return cmds, nil
+		}
+		cmds = append(cmds, cmd)
+	}
+}
+
+type position struct {
+	x int
+	y int
+}
+
+func movePosition(pos position, dir direction) position {
+	return applyCommand(pos, command{
+		dir:  dir,
+		dist: 1,
+	})
+}
+
+// applyCommand moves pos the full distance of cmd at once.
+func applyCommand(pos position, cmd command) position {
+	switch cmd.dir {
+	case UP:
+		pos.y += cmd.dist
+	case RIGHT:
+		pos.x += cmd.dist
+	case DOWN:
+		pos.y -= cmd.dist
+	case LEFT:
+		pos.x -= cmd.dist
+	case UP_RIGHT:
+		pos.x += cmd.dist
+		pos.y += cmd.dist
+	case DOWN_RIGHT:
+		pos.x += cmd.dist
+		pos.y -= cmd.dist
+	case DOWN_LEFT:
+		pos.x -= cmd.dist
+		pos.y -= cmd.dist
+	case UP_LEFT:
+		pos.x -= cmd.dist
+		pos.y += cmd.dist
+	default:
+		panic("invalid direction")
+	}
+	return pos
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func follow(head, tail position) position {
+	vec := position{
+		x: head.x - tail.x,
+		y: head.y - tail.y,
+	}
+	// adjacent
+	if abs(vec.x) <= 1 && abs(vec.y) <= 1 {
+		return tail
+	}
+	//fmt.Printf("%+v", vec)
+
+	if vec.x == 2 {
+		vec.x = 1
+	}
+	if vec.x == -2 {
+		vec.x = -1
+	}
+	if vec.y == 2 {
+		vec.y = 1
+	}
+	if vec.y == -2 {
+		vec.y = -1
+	}
+	tail.x = tail.x + vec.x
+	tail.y = tail.y + vec.y
+	return tail
+}
+
+func moveRope(pos []position, dir direction) {
+	moveRopeWith(pos, dir, clamp)
+}
+
+func moveRopeWith(pos []position, dir direction, p physics) {
+	if len(pos) < 2 {
+		panic("rope to short")
+	}
+	pos[0] = movePosition(pos[0], dir)
+	p.move(pos)
+}
+
+func solve(cmds []command, length int, p physics) int {
+	visited := map[position]int{}
+	rope := make([]position, length)
+
+	visited[rope[len(rope)-1]]++
+
+	for _, cmd := range cmds {
+		for i := 0; i < cmd.dist; i++ {
+			moveRopeWith(rope, cmd.dir, p)
+			visited[rope[len(rope)-1]]++
+		}
+	}
+	settle(rope, p, func() {
+		visited[rope[len(rope)-1]]++
+	})
+	return len(visited)
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func Run() error {
+	var (
+		animate bool
+		trail   bool
+		stats   bool
+		gifFile string
+		trjFile string
+		trjFmt  string
+		length  int
+		delay   time.Duration
+		scale   int
+		phys    string
+		slack   int
+		speed   string
+		stream  bool
+		part    solver.Part
+		format  string
+	)
+	flag.BoolVar(&animate, "animate", false, "animate the rope in the terminal")
+	flag.BoolVar(&trail, "trail", false, "print the positions visited by the tail")
+	flag.BoolVar(&stats, "stats", false, "print statistics of every knot")
+	flag.StringVar(&gifFile, "gif", "", "write the rope animation to `file`")
+	flag.StringVar(&trjFile, "trajectory", "", "write the positions of every knot after every step to `file`")
+	flag.StringVar(&trjFmt, "trajectory-format", "csv", "format of the trajectory: csv or jsonl")
+	flag.IntVar(&length, "length", 10, "number of knots of the simulated rope")
+	flag.DurationVar(&delay, "delay", 100*time.Millisecond, "delay between two frames of the animation")
+	flag.IntVar(&scale, "scale", 2, "scale of the GIF")
+	flag.StringVar(&phys, "physics", "clamp", "how knots follow: clamp, orthogonal, elastic, limited or pinned")
+	flag.IntVar(&slack, "slack", 2, "distance a knot may fall behind with elastic physics")
+	flag.StringVar(&speed, "speed", "1", "comma separated maximum speeds of the knots behind the head with limited physics")
+	flag.BoolVar(&stream, "stream", false, "read the commands one at a time and track visited positions in a bitmap")
+	flag.Var(&part, "part", "only solve part `n`")
+	flag.StringVar(&format, "format", "text", "output format of the answers: text or json")
+	flag.Parse()
+
+	out, err := solver.NewOutput(os.Stdout, 9, format)
+	if err != nil {
+		return err
+	}
+
+	speeds, err := parseSpeeds(speed)
+	if err != nil {
+		return err
+	}
+	newRopePhysics := func(length int) (physics, error) {
+		return newPhysics(phys, length, slack, speeds)
+	}
+	// the commands of a goto depend on the head, which some physics move
+	headPhysics, err := newRopePhysics(length)
+	if err != nil {
+		return err
+	}
+	noGoto := movesHead(headPhysics)
+
+	simulation := animate || trail || stats || gifFile != "" || trjFile != ""
+	if out.JSON() && (animate || trail || stats) {
+		return fmt.Errorf("-format json can not be combined with -animate, -trail or -stats")
+	}
+	if stream && simulation {
+		return fmt.Errorf("-stream does not record the simulation")
+	}
+	if flag.NArg() > 1 && (gifFile != "" || trjFile != "") {
+		return fmt.Errorf("-gif and -trajectory need a single input file")
+	}
+
+	// part 1 simulates a rope with two knots, part 2 one with ten knots
+	lengths, parts := []int{}, []int{}
+	for i, length := range []int{2, 10} {
+		if part.Has(i + 1) {
+			lengths = append(lengths, length)
+			parts = append(parts, i+1)
+		}
+	}
+
+	return out.EachInput(flag.Args(), func(r io.Reader) error {
+		if stream {
+			counts, err := solveStream(context.Background(), r, lengths, newRopePhysics)
+			if err != nil {
+				return err
+			}
+			for i, count := range counts {
+				err = out.Answer(parts[i], count)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		script := newCommandStream(r)
+		script.noGoto = noGoto
+		cmds, err := readCommands(script)
+		if err != nil {
+			return solver.InvalidInput(err)
+		}
+
+		if simulation {
+			if length < 2 {
+				return fmt.Errorf("rope to short: %d", length)
+			}
+			p, err := newRopePhysics(length)
+			if err != nil {
+				return err
+			}
+			sim := simulate(cmds, length, p)
+			if animate {
+				sim.animate(os.Stdout, delay)
+			}
+			if trail {
+				sim.printVisited(os.Stdout)
+			}
+			if stats {
+				err = sim.printStats(os.Stdout)
+				if err != nil {
+					return err
+				}
+			}
+			if trjFile != "" {
+				err = writeFile(trjFile, func(w io.Writer) error {
+					return sim.writeTrajectory(w, trjFmt)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if gifFile != "" {
+				err = writeFile(gifFile, func(w io.Writer) error {
+					return sim.writeGIF(w, delay, scale)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for i, length := range lengths {
+			p, err := newRopePhysics(length)
+			if err != nil {
+				return err
+			}
+			err = out.Answer(parts[i], solve(cmds, length, p))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,556 @@
+package solution
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"solver"
+)
+
+func Test_follow(t *testing.T) {
+	tail := position{}
+	for _, test := range []struct {
+		head     position
+		expected position
+	}{
+		// adjacent or overlapping
+		{position{0, 0}, position{0, 0}},
+		{position{1, 0}, position{0, 0}},
+		{position{-1, 0}, position{0, 0}},
+		{position{0, 1}, position{0, 0}},
+		{position{0, -1}, position{0, 0}},
+		{position{1, 1}, position{0, 0}},
+		{position{1, -1}, position{0, 0}},
+		{position{-1, 1}, position{0, 0}},
+		{position{-1, -1}, position{0, 0}},
+
+		// straight
+		{position{2, 0}, position{1, 0}},
+		{position{-2, 0}, position{-1, 0}},
+		{position{0, 2}, position{0, 1}},
+		{position{0, -2}, position{0, -1}},
+
+		// knight move
+		{position{2, 1}, position{1, 1}},
+		{position{2, -1}, position{1, -1}},
+		{position{-2, 1}, position{-1, 1}},
+		{position{-2, -1}, position{-1, -1}},
+		{position{1, 2}, position{1, 1}},
+		{position{-1, 2}, position{-1, 1}},
+		{position{1, -2}, position{1, -1}},
+		{position{-1, -2}, position{-1, -1}},
+
+		// diagonal two by two, only happens with more than two knots
+		{position{2, 2}, position{1, 1}},
+		{position{2, -2}, position{1, -1}},
+		{position{-2, 2}, position{-1, 1}},
+		{position{-2, -2}, position{-1, -1}},
+	} {
+		t.Run(fmt.Sprintf("%d_%d", test.head.x, test.head.y), func(t *testing.T) {
+			result := follow(test.head, tail)
+			if result != test.expected {
+				t.Fatalf("got=%+v, want=%+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_moveRope(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		rope     []position
+		dir      direction
+		expected []position
+	}{
+		{
+			name:     "adjacent right",
+			rope:     []position{{}, {}},
+			dir:      RIGHT,
+			expected: []position{{x: 1, y: 0}, {}},
+		},
+		{
+			name:     "move tail right",
+			rope:     []position{{x: 1, y: 0}, {}},
+			dir:      RIGHT,
+			expected: []position{{x: 2, y: 0}, {x: 1, y: 0}},
+		},
+		{
+			name:     "move tail left",
+			rope:     []position{{x: 2, y: 0}, {x: 3, y: 0}},
+			dir:      LEFT,
+			expected: []position{{x: 1, y: 0}, {x: 2, y: 0}},
+		},
+		{
+			name:     "diagonal",
+			rope:     []position{{x: 1, y: 1}, {}},
+			dir:      RIGHT,
+			expected: []position{{x: 2, y: 1}, {x: 1, y: 1}},
+		},
+		{
+			name:     "diagonal two by two",
+			rope:     []position{{x: 1, y: 1}, {}, {x: -1, y: -1}},
+			dir:      UP,
+			expected: []position{{x: 1, y: 2}, {x: 1, y: 1}, {}},
+		},
+		{
+			name:     "pass diagonal step on",
+			rope:     []position{{x: 2, y: 2}, {x: 1, y: 1}, {}},
+			dir:      UP,
+			expected: []position{{x: 2, y: 3}, {x: 2, y: 2}, {x: 1, y: 1}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			moveRope(test.rope, test.dir)
+			if !reflect.DeepEqual(test.rope, test.expected) {
+				t.Fatalf("got=%+v, want=%+v", test.rope, test.expected)
+			}
+		})
+	}
+}
+
+func Test_solve(t *testing.T) {
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cmds, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		length   int
+		expected int
+	}{
+		{2, 13},
+		{10, 1},
+	} {
+		t.Run(fmt.Sprint(test.length), func(t *testing.T) {
+			result := solve(cmds, test.length, clamp)
+			if result != test.expected {
+				t.Fatalf("got=%d, want=%d", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_Solve(t *testing.T) {
+	s, ok := solver.Lookup(2022, 9)
+	if !ok {
+		t.Fatal("day 9 not registered")
+	}
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	part1, part2, err := s.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "13" || part2 != "1" {
+		t.Fatalf("got=%s %s, want=13 1", part1, part2)
+	}
+}
+
+func Test_physics(t *testing.T) {
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cmds, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		slack int
+		speed []float64
+	}{
+		{"clamp", 0, nil},
+		{"orthogonal", 0, nil},
+		{"elastic", 2, nil},
+		{"limited", 0, []float64{0.5, 1}},
+		{"pinned", 0, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newPhysics(test.name, 10, test.slack, test.speed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sim := simulate(cmds, 10, p)
+			for i, rope := range sim.frames {
+				if test.name == "limited" {
+					checkLimited(t, i, sim.frames, test.speed)
+					continue
+				}
+				for k := 1; k < len(rope); k++ {
+					dist := chebyshev(rope[k-1], rope[k])
+					switch test.name {
+					case "orthogonal":
+						dist = abs(rope[k-1].x-rope[k].x) + abs(rope[k-1].y-rope[k].y)
+					case "elastic":
+						dist -= test.slack - 1
+					}
+					if dist > 1 {
+						t.Fatalf("frame %d: knot %d at %+v too far from %+v", i, k, rope[k], rope[k-1])
+					}
+				}
+				if test.name == "pinned" && rope[len(rope)-1] != (position{}) {
+					t.Fatalf("frame %d: tail moved to %+v", i, rope[len(rope)-1])
+				}
+			}
+
+			if test.name == "limited" {
+				// the slow knots fall behind a fast head and catch up once it stops
+				p, err := newPhysics(test.name, 10, test.slack, test.speed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sim := simulate([]command{{RIGHT, 10}}, 10, p)
+				rope := sim.frames[10]
+				if chebyshev(rope[0], rope[1]) < 2 {
+					t.Fatalf("knot 1 at %+v did not fall behind the head at %+v", rope[1], rope[0])
+				}
+				rope = sim.frames[len(sim.frames)-1]
+				if rope[0] != (position{10, 0}) {
+					t.Fatalf("head moved to %+v", rope[0])
+				}
+				for k := 1; k < len(rope); k++ {
+					if chebyshev(rope[k-1], rope[k]) > 1 {
+						t.Fatalf("stopped: knot %d at %+v did not catch up with %+v", k, rope[k], rope[k-1])
+					}
+				}
+				p, _ = newPhysics(test.name, 10, test.slack, test.speed)
+				if got, want := solve([]command{{RIGHT, 10}}, 10, p), 2; got != want {
+					t.Fatalf("got=%d, want=%d", got, want)
+				}
+				counts, err := solveStream(context.Background(), strings.NewReader("R 10"), []int{10}, func(length int) (physics, error) {
+					return newPhysics(test.name, length, test.slack, test.speed)
+				})
+				if err != nil || counts[0] != 2 {
+					t.Fatalf("stream: got=%v, %v, want=[2]", counts, err)
+				}
+			}
+		})
+	}
+}
+
+// checkLimited checks that no knot behind the head moved more than its
+// speed rounded up in frame i.
+func checkLimited(t *testing.T, i int, frames [][]position, speeds []float64) {
+	t.Helper()
+	if i == 0 {
+		return
+	}
+	for k := 1; k < len(frames[i]); k++ {
+		speed := speeds[len(speeds)-1]
+		if k-1 < len(speeds) {
+			speed = speeds[k-1]
+		}
+		moved := chebyshev(frames[i-1][k], frames[i][k])
+		if float64(moved) > math.Ceil(speed) {
+			t.Fatalf("frame %d: knot %d moved %d cells with speed %g", i, k, moved, speed)
+		}
+	}
+}
+
+func Test_limited(t *testing.T) {
+	rope := []position{{x: 1, y: 0}, {}, {}}
+	l := newLimited(len(rope), []float64{1, 0.5})
+
+	moveRopeWith(rope, RIGHT, l)
+	expected := []position{{x: 2, y: 0}, {x: 1, y: 0}, {}}
+	if !reflect.DeepEqual(rope, expected) {
+		t.Fatalf("got=%+v, want=%+v", rope, expected)
+	}
+
+	// the third knot moves every second step only
+	moveRopeWith(rope, RIGHT, l)
+	expected = []position{{x: 3, y: 0}, {x: 2, y: 0}, {x: 1, y: 0}}
+	if !reflect.DeepEqual(rope, expected) {
+		t.Fatalf("got=%+v, want=%+v", rope, expected)
+	}
+	moveRopeWith(rope, RIGHT, l)
+	expected = []position{{x: 4, y: 0}, {x: 3, y: 0}, {x: 1, y: 0}}
+	if !reflect.DeepEqual(rope, expected) {
+		t.Fatalf("got=%+v, want=%+v", rope, expected)
+	}
+}
+
+func Test_parse(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		expected []command
+	}{
+		{"puzzle", "R 4\nU 2\n", []command{{RIGHT, 4}, {UP, 2}}},
+		{"diagonal", "UL 1 DR 2", []command{{UP_LEFT, 1}, {DOWN_RIGHT, 2}}},
+		{"comment", "# start\nL 1 # left\n\n", []command{{LEFT, 1}}},
+		{"repeat", "REPEAT 2 {\n R 1\n REPEAT 2 { U 1 }\n}", []command{{RIGHT, 1}, {UP, 1}, {UP, 1}, {RIGHT, 1}, {UP, 1}, {UP, 1}}},
+		{"goto", "R 1\nG -2 3", []command{{RIGHT, 1}, {UP_LEFT, 3}}},
+		{"goto straight", "G 1 -4", []command{{DOWN_RIGHT, 1}, {DOWN, 3}}},
+		{"goto in repeat", "REPEAT 2 { G 1 0 }", []command{{RIGHT, 1}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := parse(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("got=%+v, want=%+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func Test_parse_error(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"R4", "line 1, column 1: invalid direction 'R4'"},
+		{"R 4\nU x", "line 2, column 3: invalid number 'x'"},
+		{"R 4\nU", "line 2, column 2: missing number after 'U'"},
+		{"REPEAT 2\n{ R 1", "line 2, column 1: missing '}'"},
+		{"REPEAT 2 R 1 }", "line 1, column 10: expected '{' after REPEAT 2"},
+		{"  }", "line 1, column 3: unexpected '}'"},
+	} {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := parse(strings.NewReader(test.input))
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got=%v, want=%s", err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_parse_noGoto(t *testing.T) {
+	input := "R 1\n  G 20 0\n"
+	stream := newCommandStream(strings.NewReader(input))
+	stream.noGoto = true
+	_, err := readCommands(stream)
+	expected := "line 2, column 3: G is not possible with physics which move the head"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("got=%v, want=%s", err, expected)
+	}
+
+	_, err = solveStream(context.Background(), strings.NewReader(input), []int{2}, func(int) (physics, error) {
+		return pinned{rule: follow}, nil
+	})
+	if err == nil || err.Error() != expected {
+		t.Fatalf("got=%v, want=%s", err, expected)
+	}
+}
+
+func Test_stats(t *testing.T) {
+	cmds := []command{{RIGHT, 2}, {UP, 1}, {LEFT, 2}}
+	sim := simulate(cmds, 2, clamp)
+	stats := sim.stats()
+
+	head := stats[0]
+	if len(head.visited) != 6 || head.distance != 5 {
+		t.Fatalf("head: visited=%d, distance=%f", len(head.visited), head.distance)
+	}
+	if head.min != (position{}) || head.max != (position{2, 1}) {
+		t.Fatalf("head: min=%+v, max=%+v", head.min, head.max)
+	}
+
+	tail := stats[1]
+	if len(tail.visited) != solve(cmds, 2, clamp) {
+		t.Fatalf("tail: visited=%d, want=%d", len(tail.visited), solve(cmds, 2, clamp))
+	}
+	expected := []cellCount{{position{1, 0}, 4}, {position{0, 0}, 2}}
+	if result := tail.mostVisited(2); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("tail: got=%+v, want=%+v", result, expected)
+	}
+}
+
+func Test_writeTrajectory(t *testing.T) {
+	sim := simulate([]command{{UP_RIGHT, 1}}, 2, clamp)
+	for _, test := range []struct {
+		format   string
+		expected string
+	}{
+		{"csv", "step,knot,x,y\n0,0,0,0\n0,1,0,0\n1,0,1,1\n1,1,0,0\n"},
+		{"jsonl", `{"step":0,"knot":0,"x":0,"y":0}
+{"step":0,"knot":1,"x":0,"y":0}
+{"step":1,"knot":0,"x":1,"y":1}
+{"step":1,"knot":1,"x":0,"y":0}
+`},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			buf := &strings.Builder{}
+			err := sim.writeTrajectory(buf, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.expected {
+				t.Fatalf("got=%q, want=%q", buf.String(), test.expected)
+			}
+		})
+	}
+}
+
+func Test_writeGIF(t *testing.T) {
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cmds, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := simulate(cmds, 10, clamp)
+
+	buf := &bytes.Buffer{}
+	err = sim.writeGIF(buf, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != len(sim.frames)+1 {
+		t.Fatalf("got %d frames, want %d", len(anim.Image), len(sim.frames)+1)
+	}
+
+	// every frame drawn over the previous ones shows the whole area
+	min, max := sim.bounds()
+	all := image.Rect(0, 0, max.x-min.x+1, max.y-min.y+1)
+	canvas := image.NewRGBA(anim.Image[0].Bounds())
+	expected := image.NewRGBA(canvas.Bounds())
+	visited := map[position]int{}
+	for i, frame := range anim.Image {
+		if i > 0 && frame.Bounds() == canvas.Bounds() {
+			t.Fatalf("frame %d: not a partial frame", i)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+		var rope []position
+		if i < len(sim.frames) {
+			rope = sim.frames[i]
+			visited[rope[len(rope)-1]]++
+		}
+		full := gifFrame(min, max, all, 1, rope, visited)
+		draw.Draw(expected, full.Bounds(), full, image.Point{}, draw.Src)
+		if !bytes.Equal(canvas.Pix, expected.Pix) {
+			t.Fatalf("frame %d differs from the full frame", i)
+		}
+	}
+}
+
+func Test_writeGIF_maxFrames(t *testing.T) {
+	cmds := []command{}
+	for i := 0; i < 100; i++ {
+		cmds = append(cmds, command{RIGHT, 30}, command{LEFT, 30})
+	}
+	sim := simulate(cmds, 10, clamp)
+	buf := &bytes.Buffer{}
+	err := sim.writeGIF(buf, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) > maxGIFFrames+2 {
+		t.Fatalf("got %d frames, want at most %d", len(anim.Image), maxGIFFrames+2)
+	}
+}
+
+func Test_bitmap(t *testing.T) {
+	b := newBitmap()
+	positions := []position{{0, 0}, {-1, 0}, {63, 63}, {64, 0}, {-64, -65}, {-1, 0}, {1000, -1000}, {0, 0}}
+	for _, p := range positions {
+		b.set(p)
+	}
+	if b.len() != 6 {
+		t.Fatalf("got=%d, want=6", b.len())
+	}
+}
+
+// generateMotions writes n random commands.
+func generateMotions(w io.Writer, n int, seed int64) {
+	rnd := rand.New(rand.NewSource(seed))
+	dirs := []string{"U", "D", "L", "R"}
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(w, "%s %d\n", dirs[rnd.Intn(len(dirs))], rnd.Intn(20)+1)
+	}
+}
+
+func Test_solveStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	generateMotions(buf, 5000, 1)
+	input := buf.String()
+
+	cmds, err := parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{solve(cmds, 2, clamp), solve(cmds, 10, clamp)}
+
+	result, err := solveStream(context.Background(), strings.NewReader(input), []int{2, 10}, func(int) (physics, error) {
+		return clamp, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("got=%v, want=%v", result, expected)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = solveStream(ctx, strings.NewReader(input), []int{2}, func(int) (physics, error) {
+		return clamp, nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("got=%v, want=%v", err, context.Canceled)
+	}
+}
+
+func Benchmark_solve(b *testing.B) {
+	buf := &bytes.Buffer{}
+	generateMotions(buf, 100_000, 1)
+	input := buf.Bytes()
+
+	b.Run("slice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cmds, err := parse(bytes.NewReader(input))
+			if err != nil {
+				b.Fatal(err)
+			}
+			solve(cmds, 2, clamp)
+			solve(cmds, 10, clamp)
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := solveStream(context.Background(), bytes.NewReader(input), []int{2, 10}, func(int) (physics, error) {
+				return clamp, nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package solution
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// physics moves the knots behind the head after the head moved one step.
+type physics interface {
+	move(rope []position)
+}
+
+// followRule returns the new position of a knot after the knot in front of it
+// moved.
+type followRule func(head, tail position) position
+
+func chebyshev(a, b position) int {
+	dx, dy := abs(a.x-b.x), abs(a.y-b.y)
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// step moves p one step towards target on each axis.
+func step(p, target position) position {
+	return position{
+		x: p.x + sign(target.x-p.x),
+		y: p.y + sign(target.y-p.y),
+	}
+}
+
+// chain moves every knot with rule, starting behind the head.
+type chain struct {
+	rule followRule
+}
+
+func (c chain) move(rope []position) {
+	for i := 1; i < len(rope); i++ {
+		rope[i] = c.rule(rope[i-1], rope[i])
+	}
+}
+
+// clamp is the rule of the puzzle.
+var clamp = chain{follow}
+
+// followOrthogonal keeps the tail in one of the four neighbour cells of the
+// head. The tail only moves up, down, left or right, along the axis with the
+// larger distance.
+func followOrthogonal(head, tail position) position {
+	dx, dy := head.x-tail.x, head.y-tail.y
+	if abs(dx)+abs(dy) <= 1 {
+		return tail
+	}
+	if abs(dx) >= abs(dy) {
+		tail.x += sign(dx)
+	} else {
+		tail.y += sign(dy)
+	}
+	return tail
+}
+
+// elastic lets a knot stay where it is as long as it is at most slack steps
+// away from the knot in front of it.
+func elastic(slack int) followRule {
+	return func(head, tail position) position {
+		if chebyshev(head, tail) <= slack {
+			return tail
+		}
+		return step(tail, head)
+	}
+}
+
+// lagging is implemented by physics whose knots can fall behind. Such knots
+// catch up once the head stops, see settle.
+type lagging interface {
+	// behind returns whether a knot has not caught up yet
+	behind(rope []position) bool
+}
+
+// settle moves the knots behind the standing head until all caught up and
+// calls moved after every move.
+func settle(rope []position, p physics, moved func()) {
+	l, ok := p.(lagging)
+	if !ok {
+		return
+	}
+	for l.behind(rope) {
+		p.move(rope)
+		moved()
+	}
+}
+
+// limited limits the speed of every knot to a number of steps per move of
+// the head. Slow knots fall behind and catch up once the rope stops.
+type limited struct {
+	speeds []float64
+	budget []float64
+}
+
+func newLimited(length int, speeds []float64) *limited {
+	l := &limited{
+		speeds: make([]float64, length),
+		budget: make([]float64, length),
+	}
+	// the first speed is the one of the knot behind the head, the last given
+	// speed applies to all remaining knots
+	for i := 1; i < length; i++ {
+		if i-1 < len(speeds) {
+			l.speeds[i] = speeds[i-1]
+		} else {
+			l.speeds[i] = speeds[len(speeds)-1]
+		}
+	}
+	return l
+}
+
+func (l *limited) move(rope []position) {
+	for i := 1; i < len(rope); i++ {
+		max := l.speeds[i]
+		if max < 1 {
+			max = 1
+		}
+		l.budget[i] += l.speeds[i]
+		if l.budget[i] > max {
+			l.budget[i] = max
+		}
+		for l.budget[i] >= 1 && chebyshev(rope[i-1], rope[i]) > 1 {
+			rope[i] = step(rope[i], rope[i-1])
+			l.budget[i]--
+		}
+	}
+}
+
+func (l *limited) behind(rope []position) bool {
+	for i := 1; i < len(rope); i++ {
+		if chebyshev(rope[i-1], rope[i]) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// pinned fixes the tail at anchor. After the knots followed the head they are
+// pulled back towards the anchor, so the head can not go further than the
+// rope reaches.
+type pinned struct {
+	anchor position
+	rule   followRule
+}
+
+func (p pinned) move(rope []position) {
+	chain{p.rule}.move(rope)
+	rope[len(rope)-1] = p.anchor
+	for i := len(rope) - 2; i >= 0; i-- {
+		rope[i] = p.rule(rope[i+1], rope[i])
+	}
+}
+
+// movesHead returns whether p moves the head after it followed a command, so
+// the head is not where the commands took it.
+func movesHead(p physics) bool {
+	_, ok := p.(pinned)
+	return ok
+}
+
+func parseSpeeds(input string) ([]float64, error) {
+	speeds := []float64{}
+	for _, s := range strings.Split(input, ",") {
+		speed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, err
+		}
+		if speed <= 0 {
+			return nil, fmt.Errorf("invalid speed '%s'", s)
+		}
+		speeds = append(speeds, speed)
+	}
+	return speeds, nil
+}
+
+// newPhysics returns the physics by name. The limited physics keeps state,
+// so a new physics is needed for every rope.
+func newPhysics(name string, length, slack int, speeds []float64) (physics, error) {
+	switch name {
+	case "clamp":
+		return clamp, nil
+	case "orthogonal":
+		return chain{followOrthogonal}, nil
+	case "elastic":
+		if slack < 1 {
+			return nil, fmt.Errorf("invalid slack %d", slack)
+		}
+		return chain{elastic(slack)}, nil
+	case "limited":
+		if len(speeds) == 0 {
+			return nil, fmt.Errorf("missing speeds")
+		}
+		return newLimited(length, speeds), nil
+	case "pinned":
+		return pinned{rule: follow}, nil
+	default:
+		return nil, fmt.Errorf("unknown physics '%s'", name)
+	}
+}
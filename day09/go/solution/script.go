@@ -0,0 +1,315 @@
+package solution
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A motion script is a list of statements separated by white space:
+//
+//	R 4          move the head 4 steps right (U, D, L, R, UL, UR, DL, DR)
+//	G 3 -2       move the head to x=3, y=-2, diagonally first, not possible
+//	             with physics which move the head back like pinned
+//	REPEAT 3 {   repeat the statements up to the closing brace
+//	  UR 1
+//	}
+//	# comment    everything after # up to the end of the line is ignored
+//
+// The puzzle input is a valid script.
+
+type syntaxError struct {
+	line int
+	col  int
+	msg  string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.line, e.col, e.msg)
+}
+
+type token struct {
+	text string
+	line int
+	col  int
+}
+
+func (t token) errorf(format string, a ...any) error {
+	return &syntaxError{
+		line: t.line,
+		col:  t.col,
+		msg:  fmt.Sprintf(format, a...),
+	}
+}
+
+func splitTokens(text string, line int) []token {
+	tokens := []token{}
+	text, _, _ = strings.Cut(text, "#")
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, token{text[start:i], line, start + 1})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	return tokens
+}
+
+type stmtKind int
+
+const (
+	moveStmt stmtKind = iota
+	gotoStmt
+	repeatStmt
+)
+
+type stmt struct {
+	kind   stmtKind
+	cmd    command
+	target position
+	count  int
+	body   []stmt
+	// the first token of the statement for errors
+	tok token
+}
+
+// scriptParser reads the script line by line, so only the statements of
+// the current repeat block are kept in memory.
+type scriptParser struct {
+	scanner *bufio.Scanner
+	line    int
+	pending []token
+	last    token
+}
+
+func newScriptParser(input io.Reader) *scriptParser {
+	return &scriptParser{
+		scanner: bufio.NewScanner(input),
+	}
+}
+
+// next returns the next token. At the end of the input it returns a token
+// right behind the last token for errors.
+func (p *scriptParser) next() (token, bool) {
+	for len(p.pending) == 0 {
+		if !p.scanner.Scan() {
+			if p.line == 0 {
+				return token{"", 1, 1}, false
+			}
+			return token{"", p.last.line, p.last.col + len(p.last.text)}, false
+		}
+		p.line++
+		p.pending = splitTokens(p.scanner.Text(), p.line)
+	}
+	t := p.pending[0]
+	p.pending = p.pending[1:]
+	p.last = t
+	return t, true
+}
+
+func (p *scriptParser) int(after token) (int, error) {
+	t, ok := p.next()
+	if !ok {
+		return 0, t.errorf("missing number after '%s'", after.text)
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, t.errorf("invalid number '%s'", t.text)
+	}
+	return n, nil
+}
+
+// block parses statements up to the closing brace of the block opened by
+// open.
+func (p *scriptParser) block(open token) ([]stmt, error) {
+	stmts := []stmt{}
+	for {
+		t, ok := p.next()
+		if !ok {
+			if p.scanner.Err() != nil {
+				return nil, p.scanner.Err()
+			}
+			return nil, open.errorf("missing '}'")
+		}
+		if t.text == "}" {
+			return stmts, nil
+		}
+		s, err := p.stmt(t)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s)
+	}
+}
+
+// nextStmt returns the next statement on the top level. It returns false at
+// the end of the input.
+func (p *scriptParser) nextStmt() (stmt, bool, error) {
+	t, ok := p.next()
+	if !ok {
+		return stmt{}, false, p.scanner.Err()
+	}
+	if t.text == "}" {
+		return stmt{}, false, t.errorf("unexpected '}'")
+	}
+	s, err := p.stmt(t)
+	if err != nil {
+		return stmt{}, false, err
+	}
+	return s, true, nil
+}
+
+// stmt parses the statement starting with token t.
+func (p *scriptParser) stmt(t token) (stmt, error) {
+	switch t.text {
+	case "G":
+		x, err := p.int(t)
+		if err != nil {
+			return stmt{}, err
+		}
+		y, err := p.int(t)
+		if err != nil {
+			return stmt{}, err
+		}
+		return stmt{kind: gotoStmt, target: position{x, y}, tok: t}, nil
+	case "REPEAT":
+		count, err := p.int(t)
+		if err != nil {
+			return stmt{}, err
+		}
+		if count < 0 {
+			return stmt{}, t.errorf("negative repeat count %d", count)
+		}
+		brace, ok := p.next()
+		if !ok || brace.text != "{" {
+			return stmt{}, brace.errorf("expected '{' after REPEAT %d", count)
+		}
+		body, err := p.block(brace)
+		if err != nil {
+			return stmt{}, err
+		}
+		return stmt{kind: repeatStmt, count: count, body: body}, nil
+	default:
+		dir, err := strToDir(t.text)
+		if err != nil {
+			return stmt{}, t.errorf("%s", err)
+		}
+		dist, err := p.int(t)
+		if err != nil {
+			return stmt{}, err
+		}
+		if dist < 0 {
+			return stmt{}, t.errorf("negative distance %d", dist)
+		}
+		return stmt{kind: moveStmt, cmd: command{dir, dist}}, nil
+	}
+}
+
+// frame is a repeat block which is being expanded.
+type frame struct {
+	stmts []stmt
+	next  int
+	left  int
+}
+
+// commandStream turns the statements of a script into plain commands one at
+// a time. Since the commands of a goto depend on where the head is, the
+// head position is tracked. This only works as long as the physics does not
+// move the head, otherwise noGoto has to be set to reject gotos.
+type commandStream struct {
+	parser  *scriptParser
+	head    position
+	stack   []frame
+	pending []command
+	noGoto  bool
+}
+
+func newCommandStream(input io.Reader) *commandStream {
+	return &commandStream{
+		parser: newScriptParser(input),
+	}
+}
+
+func (c *commandStream) nextStmt() (stmt, bool, error) {
+	for len(c.stack) > 0 {
+		f := &c.stack[len(c.stack)-1]
+		if f.next < len(f.stmts) {
+			f.next++
+			return f.stmts[f.next-1], true, nil
+		}
+		f.left--
+		if f.left > 0 {
+			f.next = 0
+			continue
+		}
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+	return c.parser.nextStmt()
+}
+
+// next returns the next command. It returns false at the end of the script.
+func (c *commandStream) next() (command, bool, error) {
+	for len(c.pending) == 0 {
+		s, ok, err := c.nextStmt()
+		if err != nil || !ok {
+			return command{}, false, err
+		}
+		switch s.kind {
+		case moveStmt:
+			c.pending = append(c.pending, s.cmd)
+		case gotoStmt:
+			if c.noGoto {
+				return command{}, false, s.tok.errorf("G is not possible with physics which move the head")
+			}
+			c.pending = gotoCommands(c.head, s.target)
+		case repeatStmt:
+			if s.count > 0 && len(s.body) > 0 {
+				c.stack = append(c.stack, frame{s.body, 0, s.count})
+			}
+		}
+	}
+	cmd := c.pending[0]
+	c.pending = c.pending[1:]
+	c.head = applyCommand(c.head, cmd)
+	return cmd, true, nil
+}
+
+// gotoCommands returns the commands to move from one position to another,
+// first diagonally and then straight.
+func gotoCommands(from, to position) []command {
+	cmds := []command{}
+	dx, dy := to.x-from.x, to.y-from.y
+	diagonal := abs(dx)
+	if abs(dy) < diagonal {
+		diagonal = abs(dy)
+	}
+	if diagonal > 0 {
+		dir := map[[2]int]direction{
+			{1, 1}:   UP_RIGHT,
+			{-1, 1}:  UP_LEFT,
+			{1, -1}:  DOWN_RIGHT,
+			{-1, -1}: DOWN_LEFT,
+		}[[2]int{sign(dx), sign(dy)}]
+		cmds = append(cmds, command{dir, diagonal})
+	}
+	switch {
+	case abs(dx) > diagonal && dx > 0:
+		cmds = append(cmds, command{RIGHT, dx - diagonal})
+	case abs(dx) > diagonal:
+		cmds = append(cmds, command{LEFT, -dx - diagonal})
+	case abs(dy) > diagonal && dy > 0:
+		cmds = append(cmds, command{UP, dy - diagonal})
+	case abs(dy) > diagonal:
+		cmds = append(cmds, command{DOWN, -dy - diagonal})
+	}
+	return cmds
+}
@@ -0,0 +1,278 @@
+package solution
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// simulation holds the rope after every single step. The first frame is the
+// rope at the start, at the end the knots settle.
+type simulation struct {
+	frames [][]position
+}
+
+func simulate(cmds []command, length int, p physics) *simulation {
+	rope := make([]position, length)
+	sim := &simulation{}
+	sim.record(rope)
+	for _, cmd := range cmds {
+		for i := 0; i < cmd.dist; i++ {
+			moveRopeWith(rope, cmd.dir, p)
+			sim.record(rope)
+		}
+	}
+	settle(rope, p, func() {
+		sim.record(rope)
+	})
+	return sim
+}
+
+func (s *simulation) record(rope []position) {
+	frame := make([]position, len(rope))
+	copy(frame, rope)
+	s.frames = append(s.frames, frame)
+}
+
+// bounds returns the lower left and upper right corner of the area covered by
+// all knots and the start.
+func (s *simulation) bounds() (position, position) {
+	min, max := position{}, position{}
+	for _, frame := range s.frames {
+		for _, p := range frame {
+			if p.x < min.x {
+				min.x = p.x
+			}
+			if p.y < min.y {
+				min.y = p.y
+			}
+			if p.x > max.x {
+				max.x = p.x
+			}
+			if p.y > max.y {
+				max.y = p.y
+			}
+		}
+	}
+	return min, max
+}
+
+// knotLabel returns the label of the i-th knot as used in the puzzle
+// description.
+func knotLabel(i, length int) byte {
+	switch {
+	case i == 0:
+		return 'H'
+	case length == 2:
+		return 'T'
+	case i < 10:
+		return byte('0' + i)
+	default:
+		return '*'
+	}
+}
+
+// cell returns what is drawn at p. Knots with a lower index cover the ones
+// with a higher index. Pass no rope to only draw the visited positions.
+func cell(p position, rope []position, visited map[position]int) byte {
+	for i, knot := range rope {
+		if knot == p {
+			return knotLabel(i, len(rope))
+		}
+	}
+	if p == (position{}) {
+		return 's'
+	}
+	if _, ok := visited[p]; ok {
+		return '#'
+	}
+	return '.'
+}
+
+func printFrame(w io.Writer, min, max position, rope []position, visited map[position]int) {
+	bw := bufio.NewWriter(w)
+	for y := max.y; y >= min.y; y-- {
+		for x := min.x; x <= max.x; x++ {
+			bw.WriteByte(cell(position{x, y}, rope, visited))
+		}
+		bw.WriteByte('\n')
+	}
+	bw.Flush()
+}
+
+// printVisited prints the positions visited by the tail within the bounds of
+// the simulation.
+func (s *simulation) printVisited(w io.Writer) {
+	min, max := s.bounds()
+	visited := map[position]int{}
+	for _, rope := range s.frames {
+		visited[rope[len(rope)-1]]++
+	}
+	printFrame(w, min, max, nil, visited)
+}
+
+const (
+	ansiClear = "\x1b[H\x1b[2J"
+)
+
+// animate plays the simulation in the terminal. Every frame shows the knots
+// and the trail of the tail so far, the last frame only the trail.
+func (s *simulation) animate(w io.Writer, delay time.Duration) {
+	min, max := s.bounds()
+	visited := map[position]int{}
+	for _, rope := range s.frames {
+		visited[rope[len(rope)-1]]++
+		io.WriteString(w, ansiClear)
+		printFrame(w, min, max, rope, visited)
+		time.Sleep(delay)
+	}
+	io.WriteString(w, ansiClear)
+	printFrame(w, min, max, nil, visited)
+}
+
+// 3x5 pixel glyphs for the labels drawn into the GIF
+var glyphs = map[byte][5]string{
+	'H': {"1.1", "1.1", "111", "1.1", "1.1"},
+	'T': {"111", ".1.", ".1.", ".1.", ".1."},
+	's': {".11", "1..", ".1.", "..1", "11."},
+	'*': {"...", "1.1", ".1.", "1.1", "..."},
+	'1': {".1.", "11.", ".1.", ".1.", "111"},
+	'2': {"11.", "..1", ".1.", "1..", "111"},
+	'3': {"11.", "..1", ".1.", "..1", "11."},
+	'4': {"1.1", "1.1", "111", "..1", "..1"},
+	'5': {"111", "1..", "11.", "..1", "11."},
+	'6': {".11", "1..", "111", "1.1", "111"},
+	'7': {"111", "..1", ".1.", ".1.", ".1."},
+	'8': {"111", "1.1", "111", "1.1", "111"},
+	'9': {"111", "1.1", "111", "..1", "11."},
+}
+
+// size of a cell in pixels before scaling
+const cellSize = 7
+
+const (
+	colorBackground = iota
+	colorTrail
+	colorText
+	colorHead
+	colorKnot
+	colorStart
+)
+
+var gifPalette = color.Palette{
+	colorBackground: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	colorTrail:      color.RGBA{0xcc, 0xcc, 0xcc, 0xff},
+	colorText:       color.RGBA{0xff, 0xff, 0xff, 0xff},
+	colorHead:       color.RGBA{0xd0, 0x20, 0x20, 0xff},
+	colorKnot:       color.RGBA{0x20, 0x50, 0xc0, 0xff},
+	colorStart:      color.RGBA{0x20, 0xa0, 0x40, 0xff},
+}
+
+func fillCell(img *image.Paletted, x, y, scale int, c uint8) {
+	size := cellSize * scale
+	for py := y * size; py < (y+1)*size; py++ {
+		for px := x * size; px < (x+1)*size; px++ {
+			img.SetColorIndex(px, py, c)
+		}
+	}
+}
+
+func drawGlyph(img *image.Paletted, x, y, scale int, label byte) {
+	glyph, ok := glyphs[label]
+	if !ok {
+		return
+	}
+	size := cellSize * scale
+	for gy, row := range glyph {
+		for gx := range row {
+			if row[gx] != '1' {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex(x*size+(gx+2)*scale+px, y*size+(gy+1)*scale+py, colorText)
+				}
+			}
+		}
+	}
+}
+
+// maxGIFFrames limits the frames of the GIF. Longer simulations show several
+// steps per frame.
+const maxGIFFrames = 2000
+
+// gifFrame draws the cells in the rectangle cells, in cell coordinates
+// relative to the upper left corner, into a frame of the area min to max.
+func gifFrame(min, max position, cells image.Rectangle, scale int, rope []position, visited map[position]int) *image.Paletted {
+	size := cellSize * scale
+	img := image.NewPaletted(image.Rect(cells.Min.X*size, cells.Min.Y*size, cells.Max.X*size, cells.Max.Y*size), gifPalette)
+	for cy := cells.Min.Y; cy < cells.Max.Y; cy++ {
+		for cx := cells.Min.X; cx < cells.Max.X; cx++ {
+			label := cell(position{min.x + cx, max.y - cy}, rope, visited)
+			switch label {
+			case '.':
+				continue
+			case '#':
+				fillCell(img, cx, cy, scale, colorTrail)
+				continue
+			case 's':
+				fillCell(img, cx, cy, scale, colorStart)
+			case 'H':
+				fillCell(img, cx, cy, scale, colorHead)
+			default:
+				fillCell(img, cx, cy, scale, colorKnot)
+			}
+			drawGlyph(img, cx, cy, scale, label)
+		}
+	}
+	return img
+}
+
+// writeGIF writes the simulation as an animated GIF. The first frame shows
+// the whole area, every other frame only the cells around the knots which
+// moved since the previous frame. The last frame shows the trail of the tail
+// and is shown two seconds before the animation restarts.
+func (s *simulation) writeGIF(w io.Writer, delay time.Duration, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	min, max := s.bounds()
+	all := image.Rect(0, 0, max.x-min.x+1, max.y-min.y+1)
+	// the cells changed since the previous frame
+	dirty := image.Rectangle{}
+	mark := func(rope []position) {
+		for _, p := range rope {
+			cx, cy := p.x-min.x, max.y-p.y
+			dirty = dirty.Union(image.Rect(cx, cy, cx+1, cy+1))
+		}
+	}
+
+	anim := &gif.GIF{}
+	addFrame := func(rope []position, visited map[position]int, delay int) {
+		cells := dirty
+		if len(anim.Image) == 0 {
+			cells = all
+		}
+		anim.Image = append(anim.Image, gifFrame(min, max, cells, scale, rope, visited))
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+		dirty = image.Rectangle{}
+		mark(rope)
+	}
+
+	steps := (len(s.frames) + maxGIFFrames - 1) / maxGIFFrames
+	frameDelay := int(delay / (10 * time.Millisecond))
+	visited := map[position]int{}
+	for i, rope := range s.frames {
+		visited[rope[len(rope)-1]]++
+		mark(rope)
+		if i%steps == 0 || i == len(s.frames)-1 {
+			addFrame(rope, visited, frameDelay)
+		}
+	}
+	addFrame(nil, visited, 200)
+	return gif.EncodeAll(w, anim)
+}
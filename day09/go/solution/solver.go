@@ -0,0 +1,25 @@
+package solution
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"solver"
+)
+
+func init() {
+	solver.Register(2022, 9, solver.Func(Solve))
+}
+
+// Solve returns the number of positions visited by the tail of a rope with
+// two and with ten knots.
+func Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	counts, err := solveStream(ctx, input, []int{2, 10}, func(int) (physics, error) {
+		return clamp, nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(counts[0]), strconv.Itoa(counts[1]), nil
+}
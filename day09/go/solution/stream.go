@@ -0,0 +1,108 @@
+package solution
+
+import (
+	"context"
+	"io"
+
+	"solver"
+)
+
+// chunks of 64x64 cells, one uint64 per row
+const chunkBits = 6
+
+type chunk [1 << chunkBits]uint64
+
+// bitmap is a set of positions stored in chunks of bits. Compared to a map
+// with one entry per position it needs one bit per cell of a visited area.
+type bitmap struct {
+	chunks map[position]*chunk
+	count  int
+
+	// the last used chunk, most steps stay within the same chunk
+	lastKey   position
+	lastChunk *chunk
+}
+
+func newBitmap() *bitmap {
+	return &bitmap{
+		chunks: map[position]*chunk{},
+	}
+}
+
+// set adds p to the set.
+func (b *bitmap) set(p position) {
+	key := position{p.x >> chunkBits, p.y >> chunkBits}
+	c := b.lastChunk
+	if c == nil || key != b.lastKey {
+		c = b.chunks[key]
+		if c == nil {
+			c = &chunk{}
+			b.chunks[key] = c
+		}
+		b.lastKey = key
+		b.lastChunk = c
+	}
+	mask := uint64(1) << (p.x & (1<<chunkBits - 1))
+	row := &c[p.y&(1<<chunkBits-1)]
+	if *row&mask == 0 {
+		*row |= mask
+		b.count++
+	}
+}
+
+// len returns the number of positions in the set.
+func (b *bitmap) len() int {
+	return b.count
+}
+
+// solveStream reads the commands one at a time and moves a rope for each of
+// the lengths at once. It returns the number of positions visited by the
+// tail of each rope. It stops early if ctx is done. Gotos are rejected if
+// one of the physics moves the head.
+func solveStream(ctx context.Context, input io.Reader, lengths []int, newRopePhysics func(int) (physics, error)) ([]int, error) {
+	ropes := make([][]position, len(lengths))
+	phys := make([]physics, len(lengths))
+	visited := make([]*bitmap, len(lengths))
+	for i, length := range lengths {
+		p, err := newRopePhysics(length)
+		if err != nil {
+			return nil, err
+		}
+		ropes[i] = make([]position, length)
+		phys[i] = p
+		visited[i] = newBitmap()
+		visited[i].set(position{})
+	}
+
+	stream := newCommandStream(input)
+	for _, p := range phys {
+		stream.noGoto = stream.noGoto || movesHead(p)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cmd, ok, err := stream.next()
+		if err != nil {
+			return nil, solver.InvalidInput(err)
+		}
+		if !ok {
+			break
+		}
+		for step := 0; step < cmd.dist; step++ {
+			for i, rope := range ropes {
+				moveRopeWith(rope, cmd.dir, phys[i])
+				visited[i].set(rope[len(rope)-1])
+			}
+		}
+	}
+
+	counts := make([]int, len(lengths))
+	for i, rope := range ropes {
+		settle(rope, phys[i], func() {
+			visited[i].set(rope[len(rope)-1])
+		})
+		counts[i] = visited[i].len()
+	}
+	return counts, nil
+}
@@ -0,0 +1,163 @@
+package solution
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+type trajectoryPoint struct {
+	Step int `json:"step"`
+	Knot int `json:"knot"`
+	X    int `json:"x"`
+	Y    int `json:"y"`
+}
+
+func (s *simulation) points(fn func(trajectoryPoint) error) error {
+	for step, rope := range s.frames {
+		for knot, p := range rope {
+			err := fn(trajectoryPoint{step, knot, p.x, p.y})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeTrajectory writes the position of every knot after every step as csv
+// or as JSON lines (jsonl).
+func (s *simulation) writeTrajectory(w io.Writer, format string) error {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		err := cw.Write([]string{"step", "knot", "x", "y"})
+		if err != nil {
+			return err
+		}
+		err = s.points(func(p trajectoryPoint) error {
+			return cw.Write([]string{
+				strconv.Itoa(p.Step),
+				strconv.Itoa(p.Knot),
+				strconv.Itoa(p.X),
+				strconv.Itoa(p.Y),
+			})
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		return s.points(func(p trajectoryPoint) error {
+			return enc.Encode(p)
+		})
+	default:
+		return fmt.Errorf("unknown format '%s'", format)
+	}
+}
+
+type knotStats struct {
+	// number of steps the knot spent on a position
+	visited  map[position]int
+	min      position
+	max      position
+	distance float64
+}
+
+type cellCount struct {
+	pos   position
+	count int
+}
+
+// mostVisited returns the n most visited positions. Ties are ordered by
+// position to get a stable result.
+func (k *knotStats) mostVisited(n int) []cellCount {
+	cells := []cellCount{}
+	for p, count := range k.visited {
+		cells = append(cells, cellCount{p, count})
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].count != cells[j].count {
+			return cells[i].count > cells[j].count
+		}
+		if cells[i].pos.y != cells[j].pos.y {
+			return cells[i].pos.y < cells[j].pos.y
+		}
+		return cells[i].pos.x < cells[j].pos.x
+	})
+	if n < len(cells) {
+		cells = cells[:n]
+	}
+	return cells
+}
+
+// stats returns the statistics of every knot. The distance is the euclidean
+// length of the path, so a diagonal step counts as √2.
+func (s *simulation) stats() []knotStats {
+	if len(s.frames) == 0 {
+		return nil
+	}
+	stats := make([]knotStats, len(s.frames[0]))
+	for i := range stats {
+		stats[i].visited = map[position]int{}
+	}
+	for step, rope := range s.frames {
+		for i, p := range rope {
+			k := &stats[i]
+			k.visited[p]++
+			if step == 0 {
+				k.min, k.max = p, p
+				continue
+			}
+			prev := s.frames[step-1][i]
+			k.distance += math.Hypot(float64(p.x-prev.x), float64(p.y-prev.y))
+			if p.x < k.min.x {
+				k.min.x = p.x
+			}
+			if p.y < k.min.y {
+				k.min.y = p.y
+			}
+			if p.x > k.max.x {
+				k.max.x = p.x
+			}
+			if p.y > k.max.y {
+				k.max.y = p.y
+			}
+		}
+	}
+	return stats
+}
+
+func (s *simulation) printStats(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "knot\tvisited\tdistance\tbounding box\tmost visited")
+	stats := s.stats()
+	for i, k := range stats {
+		most := ""
+		for j, cell := range k.mostVisited(3) {
+			if j > 0 {
+				most += ", "
+			}
+			most += fmt.Sprintf("%d,%d (%d)", cell.pos.x, cell.pos.y, cell.count)
+		}
+		name := string(knotLabel(i, len(stats)))
+		if i >= 10 {
+			name = strconv.Itoa(i)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%d,%d %d,%d\t%s\n",
+			name,
+			len(k.visited),
+			k.distance,
+			k.min.x, k.min.y, k.max.x, k.max.y,
+			most,
+		)
+	}
+	return tw.Flush()
+}
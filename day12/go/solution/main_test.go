@@ -0,0 +1,89 @@
+package solution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"solver"
+)
+
+func Test_getPos(t *testing.T) {
+	data := []byte{
+		'a', 'b', 'c',
+		'd', 'e', 'f',
+		'g', 'h', 'i',
+	}
+	f := field{
+		data:    data,
+		lineLen: 3,
+	}
+
+	for _, test := range []struct {
+		pos         int
+		dir         Direction
+		expectedPos int
+	}{
+		{0, LEFT, -1},
+		{0, UP, -1},
+		{1, UP, -1},
+		{1, DOWN, 4},
+		{8, DOWN, -1},
+		{8, RIGHT, -1},
+		{7, DOWN, -1},
+		{6, RIGHT, 7},
+	} {
+		t.Run(fmt.Sprintf("%d_%v", test.pos, test.dir), func(t *testing.T) {
+			pos, _ := f.getPos(test.pos, test.dir)
+			if pos != test.expectedPos {
+				t.Fatalf("got=%d, want=%d", pos, test.expectedPos)
+			}
+		})
+	}
+}
+
+func Test_Solve(t *testing.T) {
+	s, ok := solver.Lookup(2022, 12)
+	if !ok {
+		t.Fatal("day 12 not registered")
+	}
+	f, err := os.Open("../../sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	part1, part2, err := s.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "31" || part2 != "29" {
+		t.Fatalf("got=%s %s, want=31 29", part1, part2)
+	}
+}
+
+func Test_Solve_error(t *testing.T) {
+	s, _ := solver.Lookup(2022, 12)
+	for _, test := range []struct {
+		name     string
+		input    string
+		expected string
+		code     int
+	}{
+		{"length", "Sab\nabE\nab\n", "line 3: length 2 differs from 3", solver.ExitInput},
+		{"elevation", "Sab\naBE\n", "line 2, column 2: invalid elevation 'B'", solver.ExitInput},
+		{"end", "Sab\nabc\n", "start S or end E missing", solver.ExitInput},
+		{"no path", "Saz\nzzE\n", "no path found", solver.ExitNoSolution},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := s.Solve(context.Background(), strings.NewReader(test.input))
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got=%v, want=%s", err, test.expected)
+			}
+			if code := solver.ExitCode(err); code != test.code {
+				t.Fatalf("exit code: got=%d, want=%d", code, test.code)
+			}
+		})
+	}
+}
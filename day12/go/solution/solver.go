@@ -0,0 +1,31 @@
+package solution
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"solver"
+)
+
+func init() {
+	solver.Register(2022, 12, solver.Func(Solve))
+}
+
+// Solve returns the fewest steps from the start to the end and from any
+// square with elevation a to the end.
+func Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	data, err := readInput(input)
+	if err != nil {
+		return "", "", solver.InvalidInput(err)
+	}
+	dist, err := path(data, data.start(), data.end())
+	if err != nil {
+		return "", "", err
+	}
+	min, err := shortestHike(ctx, data)
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(dist), strconv.Itoa(min), nil
+}
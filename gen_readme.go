@@ -1,67 +1,379 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-var langNames = map[string]string{
-	"sh": "Shell",
+type language struct {
+	name string
+	// extensions of the source files
+	exts []string
 }
 
-var text = `# Advent of Code 2022
+// languages by directory
+var languages = map[string]language{
+	"c":    {"C", []string{".c", ".h"}},
+	"go":   {"Go", []string{".go"}},
+	"rust": {"Rust", []string{".rs"}},
+	"sh":   {"Shell", []string{".sh"}},
+}
+
+// the generated part of the README is between these markers, the rest is
+// kept as it is
+const (
+	beginMarker = "<!-- BEGIN GENERATED: go run gen_readme.go -->"
+	endMarker   = "<!-- END GENERATED -->"
+)
 
-Advent of code solved in various languages:
-`
+// the days of legacyYear may still be in the root directory
+const legacyYear = 2022
 
-func main() {
-	langs := map[string][]string{}
+type year struct {
+	year int
+	dir  string
+}
+
+type day struct {
+	name  string
+	path  string
+	langs map[string]bool
+	// parts with an answer for the real input
+	stars int
+}
+
+// countStars returns the number of parts with an answer for input.txt in the
+// answers file of a day.
+func countStars(file string) (int, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	parts := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "input.txt" {
+			continue
+		}
+		parts[fields[1]] = true
+	}
+	return len(parts), scanner.Err()
+}
+
+// countLines returns the number of lines of the source files in dir without
+// tests.
+func countLines(dir string, lang language) (int, error) {
+	lines := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == "target" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		for _, ext := range lang.exts {
+			if filepath.Ext(path) != ext {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			lines += bytes.Count(data, []byte("\n"))
+		}
+		return nil
+	})
+	return lines, err
+}
+
+// readRuntimes returns the mean wall time of each solution from the last
+// run of aoc bench by year, day and language. The real input is preferred over
+// the sample.
+func readRuntimes(file string) (map[string]time.Duration, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report := struct {
+		Results []struct {
+			Year   int    `json:"year"`
+			Day    int    `json:"day"`
+			Lang   string `json:"lang"`
+			Input  string `json:"input"`
+			WallNS int64  `json:"wall_ns"`
+			Error  string `json:"error"`
+		} `json:"results"`
+	}{}
+	err = json.Unmarshal(data, &report)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	runtimes := map[string]time.Duration{}
+	for _, r := range report.Results {
+		if r.Year == 0 {
+			r.Year = legacyYear
+		}
+		key := fmt.Sprintf("%d/day%02d/%s", r.Year, r.Day, r.Lang)
+		if r.Error != "" {
+			continue
+		}
+		if _, ok := runtimes[key]; ok && r.Input != "input.txt" {
+			continue
+		}
+		runtimes[key] = time.Duration(r.WallNS).Round(10 * time.Microsecond)
+	}
+	return runtimes, nil
+}
+
+// findYears returns the directories with the days of each year, the latest
+// year first. The days of legacyYear may still be in the root directory.
+func findYears() ([]year, error) {
 	entries, err := os.ReadDir(".")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	years := []year{}
+	legacy := false
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(entry.Name()); err == nil && len(entry.Name()) == 4 {
+			years = append(years, year{n, entry.Name()})
+		}
+		if strings.HasPrefix(entry.Name(), "day") {
+			legacy = true
+		}
+	}
+	if legacy {
+		years = append(years, year{legacyYear, "."})
+	}
+	sort.Slice(years, func(i, j int) bool {
+		return years[i].year > years[j].year
+	})
+	return years, nil
+}
+
+// languageStats counts the days and lines of code of a language.
+type languageStats struct {
+	days  int
+	lines int
+}
+
+// readDays returns the days of a year and adds their languages to stats.
+func readDays(y year, stats map[string]*languageStats) ([]day, error) {
+	entries, err := os.ReadDir(y.dir)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, day := range entries {
-		if !strings.HasPrefix(day.Name(), "day") {
+	days := []day{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "day") {
 			continue
 		}
+		d := day{
+			name:  entry.Name(),
+			path:  filepath.ToSlash(filepath.Join(y.dir, entry.Name())),
+			langs: map[string]bool{},
+		}
+		d.stars, err = countStars(filepath.Join(d.path, "answers.txt"))
+		if err != nil {
+			return nil, err
+		}
 
-		langsOfDay, err := os.ReadDir(day.Name())
+		langsOfDay, err := os.ReadDir(d.path)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		for _, lang := range langsOfDay {
-			if strings.HasSuffix(lang.Name(), ".txt") {
+		for _, entry := range langsOfDay {
+			if !entry.IsDir() {
 				continue
 			}
-			langs[lang.Name()] = append(langs[lang.Name()], day.Name())
+			lang, ok := languages[entry.Name()]
+			if !ok {
+				return nil, fmt.Errorf("unknown language '%s', add it to languages", entry.Name())
+			}
+			n, err := countLines(filepath.Join(d.path, entry.Name()), lang)
+			if err != nil {
+				return nil, err
+			}
+			d.langs[entry.Name()] = true
+			if stats[entry.Name()] == nil {
+				stats[entry.Name()] = &languageStats{}
+			}
+			stats[entry.Name()].days++
+			stats[entry.Name()].lines += n
 		}
+		days = append(days, d)
 	}
+	return days, nil
+}
 
+// writeProgress writes the table with the days of a year.
+func writeProgress(buf *bytes.Buffer, y year, days []day, runtimes map[string]time.Duration) {
+	used := map[string]bool{}
+	for _, d := range days {
+		for lang := range d.langs {
+			used[lang] = true
+		}
+	}
 	langOrder := []string{}
-	for lang := range langs {
+	for lang := range used {
 		langOrder = append(langOrder, lang)
 	}
-
 	sort.Strings(langOrder)
 
-	buf := bytes.NewBuffer(nil)
+	buf.WriteString("| day | stars |")
 	for _, lang := range langOrder {
-		langName, ok := langNames[lang]
-		if !ok {
-			langName = strings.Title(lang)
+		buf.WriteString(" " + languages[lang].name + " |")
+	}
+	buf.WriteString("\n|-----|-------|")
+	for range langOrder {
+		buf.WriteString("---|")
+	}
+	buf.WriteString("\n")
+
+	totalStars := 0
+	daysOfLang := map[string]int{}
+	for _, d := range days {
+		totalStars += d.stars
+		buf.WriteString("| " + d.name + " | " + strings.Repeat("⭐", d.stars) + " |")
+		for _, lang := range langOrder {
+			if !d.langs[lang] {
+				buf.WriteString(" |")
+				continue
+			}
+			daysOfLang[lang]++
+			cell := "[✔](" + d.path + "/" + lang + ")"
+			if runtime, ok := runtimes[fmt.Sprintf("%d/%s/%s", y.year, d.name, lang)]; ok {
+				cell += " " + runtime.String()
+			}
+			buf.WriteString(" " + cell + " |")
 		}
-		buf.WriteString("* " + langName + "\n")
-		sort.Strings(langs[lang])
-		for _, day := range langs[lang] {
-			buf.WriteString("  * [" + day + "](" + day + "/" + lang + ")\n")
+		buf.WriteString("\n")
+	}
+	buf.WriteString("| total | " + strconv.Itoa(totalStars) + " |")
+	for _, lang := range langOrder {
+		buf.WriteString(" " + strconv.Itoa(daysOfLang[lang]) + " |")
+	}
+	buf.WriteString("\n")
+}
+
+func generate() (string, error) {
+	years, err := findYears()
+	if err != nil {
+		return "", err
+	}
+	runtimes, err := readRuntimes("bench.json")
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	stats := map[string]*languageStats{}
+	for _, y := range years {
+		days, err := readDays(y, stats)
+		if err != nil {
+			return "", err
 		}
+		fmt.Fprintf(buf, "## %d\n\n", y.year)
+		writeProgress(buf, y, days, runtimes)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("Stars are the parts with an answer for the real input in the answers.txt\n")
+	buf.WriteString("of the day. Runtimes are the mean wall times of the last `aoc bench` with the\n")
+	buf.WriteString("real input or the sample if the real input is missing.\n")
+
+	langOrder := []string{}
+	for lang := range stats {
+		langOrder = append(langOrder, lang)
+	}
+	sort.Strings(langOrder)
+	buf.WriteString("\n## Languages\n\n| language | days | lines |\n|----------|-----:|------:|\n")
+	for _, lang := range langOrder {
+		fmt.Fprintf(buf, "| %s | %d | %d |\n", languages[lang].name, stats[lang].days, stats[lang].lines)
+	}
+
+	// timings written by aoc bench
+	timings, err := os.ReadFile("bench.md")
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err == nil {
+		buf.WriteString("\n## Timings\n\n")
+		buf.Write(timings)
+	}
+	return buf.String(), nil
+}
+
+// replaceSection replaces the text between the markers with section.
+func replaceSection(readme, section string) (string, error) {
+	begin := strings.Index(readme, beginMarker)
+	end := strings.Index(readme, endMarker)
+	if begin == -1 || end == -1 || end < begin {
+		return "", fmt.Errorf("markers '%s' and '%s' not found", beginMarker, endMarker)
+	}
+	begin += len(beginMarker)
+	return readme[:begin] + "\n" + section + readme[end:], nil
+}
+
+func main() {
+	var (
+		readmeFile string
+		check      bool
+	)
+	flag.StringVar(&readmeFile, "readme", "README.md", "README to update")
+	flag.BoolVar(&check, "check", false, "only check if the README is up to date")
+	flag.Parse()
+
+	section, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	readme, err := os.ReadFile(readmeFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	updated, err := replaceSection(string(readme), section)
+	if err != nil {
+		log.Fatalf("%s: %s", readmeFile, err)
 	}
 
-	fmt.Println(text)
-	fmt.Println(buf.String())
+	if check {
+		if updated != string(readme) {
+			log.Fatalf("%s is out of date, run go run gen_readme.go", readmeFile)
+		}
+		return
+	}
+	if updated == string(readme) {
+		return
+	}
+	err = os.WriteFile(readmeFile, []byte(updated), 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -10,8 +10,24 @@ import (
 	"strconv"
 )
 
-func getDayDirName(day int) string {
-	return fmt.Sprintf("day%.02d", day)
+// the days of legacyYear may still be in the root directory
+const legacyYear = 2022
+
+// getDayDirName returns the directory of a day in YYYY/dayNN. The days of
+// legacyYear are in the root directory as long as they are not migrated.
+func getDayDirName(year, day int) string {
+	dayDir := fmt.Sprintf("day%.02d", day)
+	yearDir := strconv.Itoa(year)
+	if year != legacyYear {
+		return filepath.Join(yearDir, dayDir)
+	}
+	if _, err := os.Stat(yearDir); err == nil {
+		return filepath.Join(yearDir, dayDir)
+	}
+	if legacy, _ := filepath.Glob("day[0-9][0-9]"); len(legacy) > 0 {
+		return dayDir
+	}
+	return filepath.Join(yearDir, dayDir)
 }
 
 func getInput(year, day int) ([]byte, error) {
@@ -51,8 +67,9 @@ func getInput(year, day int) ([]byte, error) {
 
 func run() error {
 	var (
-		year = 2022
+		year int
 	)
+	flag.IntVar(&year, "year", legacyYear, "year of the event")
 	flag.Parse()
 
 	if flag.NArg() < 1 {
@@ -69,7 +86,7 @@ func run() error {
 		return err
 	}
 
-	dayDir := getDayDirName(day)
+	dayDir := getDayDirName(year, day)
 	err = os.MkdirAll(dayDir, 0750)
 	if err != nil {
 		return err
@@ -85,6 +102,8 @@ func run() error {
 		return err
 	}
 	f.Close()
+	// input.txt is ignored by git, only the encrypted input may be committed
+	fmt.Printf("wrote %s, encrypt it with 'cd aoc && go run . encrypt -year %d -rm'\n", inputFile, year)
 
 	sampleFile := filepath.Join(dayDir, "sample.txt")
 	f, err = os.Create(sampleFile)
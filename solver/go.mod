@@ -0,0 +1,3 @@
+module solver
+
+go 1.19
@@ -0,0 +1,54 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Part selects the part of a puzzle to solve. The zero value selects both
+// parts. It implements flag.Value, e.g. flag.Var(&part, "part", ...).
+type Part int
+
+func (p *Part) String() string {
+	if p == nil || *p == 0 {
+		return ""
+	}
+	return fmt.Sprint(int(*p))
+}
+
+func (p *Part) Set(s string) error {
+	switch s {
+	case "1":
+		*p = 1
+	case "2":
+		*p = 2
+	default:
+		return fmt.Errorf("invalid part '%s', expected 1 or 2", s)
+	}
+	return nil
+}
+
+// Has returns whether part n has to be solved.
+func (p Part) Has(n int) bool {
+	return p == 0 || int(p) == n
+}
+
+// Stdin is the input name which reads from standard input.
+const Stdin = "-"
+
+func solveInput(name string, solve func(r io.Reader) error) error {
+	if name == Stdin {
+		return solve(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = solve(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package solver
+
+import "testing"
+
+func Test_Part(t *testing.T) {
+	for _, test := range []struct {
+		value    string
+		expected []bool
+	}{
+		{"", []bool{true, true}},
+		{"1", []bool{true, false}},
+		{"2", []bool{false, true}},
+	} {
+		var p Part
+		if test.value != "" {
+			err := p.Set(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		got := []bool{p.Has(1), p.Has(2)}
+		if got[0] != test.expected[0] || got[1] != test.expected[1] {
+			t.Fatalf("%s: got=%v, want=%v", test.value, got, test.expected)
+		}
+	}
+	var p Part
+	if p.Set("3") == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,149 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Exit codes of the Go solutions. The flag package exits with 2 on invalid
+// flags.
+const (
+	ExitError      = 1
+	ExitInput      = 3
+	ExitNoSolution = 4
+)
+
+// InputError is returned if the input of a puzzle can not be parsed.
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidInput marks err as an error in the input. It returns nil if err is
+// nil.
+func InvalidInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &InputError{err}
+}
+
+// NoSolutionError is returned if a valid input has no solution.
+type NoSolutionError struct {
+	Msg string
+}
+
+func (e *NoSolutionError) Error() string {
+	return e.Msg
+}
+
+// ExitCode returns the exit code for the error of a solution.
+func ExitCode(err error) int {
+	var (
+		input      *InputError
+		noSolution *NoSolutionError
+	)
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &input):
+		return ExitInput
+	case errors.As(err, &noSolution):
+		return ExitNoSolution
+	default:
+		return ExitError
+	}
+}
+
+// Answer is the JSON record of the answer of a part.
+type Answer struct {
+	Day        int    `json:"day"`
+	Part       int    `json:"part"`
+	Input      string `json:"input"`
+	Answer     string `json:"answer"`
+	DurationNS int64  `json:"duration_ns"`
+}
+
+// Output prints the answers of a day as text, one answer per line, or as
+// JSON records.
+type Output struct {
+	w      io.Writer
+	day    int
+	json   bool
+	input  string
+	inputs int
+	start  time.Time
+}
+
+// NewOutput returns the output of day in format text or json.
+func NewOutput(w io.Writer, day int, format string) (*Output, error) {
+	o := &Output{w: w, day: day}
+	switch format {
+	case "text":
+	case "json":
+		o.json = true
+	default:
+		return nil, fmt.Errorf("unknown format '%s', expected text or json", format)
+	}
+	return o, nil
+}
+
+// JSON returns whether the answers are printed as JSON.
+func (o *Output) JSON() bool {
+	return o.json
+}
+
+// EachInput calls solve for every input file in names and adds the name of
+// the file to its errors. No names or the name Stdin reads standard input.
+// If there are several inputs, the text output starts the results of every
+// input with its name.
+func (o *Output) EachInput(names []string, solve func(r io.Reader) error) error {
+	if len(names) == 0 {
+		names = []string{Stdin}
+	}
+	for i, name := range names {
+		if len(names) > 1 && !o.json {
+			if i > 0 {
+				fmt.Fprintln(o.w)
+			}
+			fmt.Fprintf(o.w, "%s:\n", name)
+		}
+		o.input = name
+		o.start = time.Now()
+		err := solveInput(name, solve)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Answer prints the answer of a part. The duration of an answer is the time
+// since the previous answer or since the input was opened, so the first
+// answer includes parsing the input.
+func (o *Output) Answer(part int, answer interface{}) error {
+	now := time.Now()
+	duration := now.Sub(o.start)
+	o.start = now
+	if !o.json {
+		_, err := fmt.Fprintln(o.w, answer)
+		return err
+	}
+	return json.NewEncoder(o.w).Encode(Answer{
+		Day:        o.day,
+		Part:       part,
+		Input:      o.input,
+		Answer:     fmt.Sprint(answer),
+		DurationNS: duration.Nanoseconds(),
+	})
+}
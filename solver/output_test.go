@@ -0,0 +1,112 @@
+package solver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Output_EachInput(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+	for _, file := range files {
+		err := os.WriteFile(file, []byte(filepath.Base(file)), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	o, err := NewOutput(out, 1, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.EachInput(files, func(r io.Reader) error {
+		_, err := io.Copy(out, r)
+		out.WriteString("\n")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := files[0] + ":\na.txt\n\n" + files[1] + ":\nb.txt\n"
+	if out.String() != expected {
+		t.Fatalf("got=%q, want=%q", out.String(), expected)
+	}
+
+	err = o.EachInput(files[:1], func(r io.Reader) error {
+		return io.ErrUnexpectedEOF
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), files[0]+": ") {
+		t.Fatalf("got=%v, want error with file name", err)
+	}
+}
+
+func Test_Output_json(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "input.txt")
+	err := os.WriteFile(file, []byte("$ ls\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	o, err := NewOutput(out, 7, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.EachInput([]string{file, file}, func(r io.Reader) error {
+		err := o.Answer(1, 95437)
+		if err != nil {
+			return err
+		}
+		return o.Answer(2, "24933642")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := json.NewDecoder(out)
+	answers := []Answer{}
+	for d.More() {
+		a := Answer{}
+		err := d.Decode(&a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.DurationNS = 0
+		answers = append(answers, a)
+	}
+	expected := Answer{Day: 7, Part: 2, Input: file, Answer: "24933642"}
+	if len(answers) != 4 || answers[3] != expected {
+		t.Fatalf("got=%+v, want 4 answers ending with %+v", answers, expected)
+	}
+
+	_, err = NewOutput(out, 7, "xml")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_ExitCode(t *testing.T) {
+	for _, test := range []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{errors.New("open input.txt: no such file"), ExitError},
+		{InvalidInput(errors.New("invalid line 'x'")), ExitInput},
+		{fmt.Errorf("input.txt: %w", InvalidInput(errors.New("invalid line 'x'"))), ExitInput},
+		{fmt.Errorf("input.txt: %w", &NoSolutionError{"no path found"}), ExitNoSolution},
+	} {
+		if code := ExitCode(test.err); code != test.expected {
+			t.Fatalf("%v: got=%d, want=%d", test.err, code, test.expected)
+		}
+	}
+	if InvalidInput(nil) != nil {
+		t.Fatal("InvalidInput(nil) is not nil")
+	}
+}
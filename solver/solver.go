@@ -0,0 +1,85 @@
+// Package solver is the common interface of the Go solutions. Every day
+// registers its solver, so the solutions can be run from code.
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Solver solves both parts of the puzzle of a day. Solve returns the error
+// of ctx if ctx is done before the parts are solved.
+type Solver interface {
+	Solve(ctx context.Context, input io.Reader) (part1, part2 string, err error)
+}
+
+// Func is a function implementing Solver.
+type Func func(ctx context.Context, input io.Reader) (string, string, error)
+
+func (f Func) Solve(ctx context.Context, input io.Reader) (string, string, error) {
+	return f(ctx, input)
+}
+
+// Day identifies the puzzle of a day.
+type Day struct {
+	Year int
+	Day  int
+}
+
+var (
+	mu      sync.Mutex
+	solvers = map[Day]Solver{}
+)
+
+// Register registers the solver of a day. It panics if the day is already
+// registered.
+func Register(year, day int, s Solver) {
+	mu.Lock()
+	defer mu.Unlock()
+	d := Day{year, day}
+	if _, ok := solvers[d]; ok {
+		panic(fmt.Sprintf("solver of day %d of %d registered twice", day, year))
+	}
+	solvers[d] = s
+}
+
+// Lookup returns the solver of a day.
+func Lookup(year, day int) (Solver, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	s, ok := solvers[Day{year, day}]
+	return s, ok
+}
+
+// Years returns the years with registered days in ascending order.
+func Years() []int {
+	mu.Lock()
+	defer mu.Unlock()
+	seen := map[int]bool{}
+	years := []int{}
+	for d := range solvers {
+		if !seen[d.Year] {
+			seen[d.Year] = true
+			years = append(years, d.Year)
+		}
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Days returns the registered days of a year in ascending order.
+func Days(year int) []int {
+	mu.Lock()
+	defer mu.Unlock()
+	days := []int{}
+	for d := range solvers {
+		if d.Year == year {
+			days = append(days, d.Day)
+		}
+	}
+	sort.Ints(days)
+	return days
+}